@@ -0,0 +1,99 @@
+package bru
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// An Assertion is an entry of an assert block, such as `res.status: eq 200`
+type Assertion struct {
+	Expr     string // expression evaluated, e.g. res.status
+	Operator string // operator, e.g. eq, empty if the value has none and is then compared with eq
+	Value    string // expected value, empty for unary operators
+	Enabled  bool
+}
+
+// assertOperators lists the binary operators supported by Bruno
+var assertOperators = map[string]bool{
+	"eq": true, "neq": true, "gt": true, "gte": true, "lt": true, "lte": true,
+	"in": true, "notIn": true, "contains": true, "notContains": true, "length": true,
+	"matches": true, "notMatches": true, "startsWith": true, "endsWith": true, "between": true,
+}
+
+// unaryAssertOperators lists the operators supported by Bruno that take no value
+var unaryAssertOperators = map[string]bool{
+	"isEmpty": true, "isNotEmpty": true, "isNull": true, "isUndefined": true, "isDefined": true,
+	"isTruthy": true, "isFalsy": true, "isJson": true, "isNumber": true, "isString": true,
+	"isBoolean": true, "isArray": true,
+}
+
+// ParseAssertion splits the value of an assert block entry in an operator and a value.
+// Like in Bruno, a value not starting with a known operator is compared with eq,
+// the operator of the assertion being left empty to write the value back as it was.
+// The RE2 patterns of matches and notMatches are compiled so that invalid ones are reported here.
+func ParseAssertion(e DictionaryElement) (Assertion, error) {
+	a := Assertion{Expr: e.Key, Enabled: !e.Disabled}
+	op, value, _ := strings.Cut(strings.TrimSpace(e.Value), " ")
+	switch {
+	case unaryAssertOperators[op]:
+		a.Operator = op
+	case assertOperators[op]:
+		a.Operator = op
+		a.Value = strings.TrimSpace(value)
+	default:
+		a.Value = strings.TrimSpace(e.Value)
+	}
+	if a.Operator == "matches" || a.Operator == "notMatches" {
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return a, fmt.Errorf("assert %s: invalid pattern: %w", a.Expr, err)
+		}
+	}
+	return a, nil
+}
+
+// Element returns the assert block entry of the assertion
+func (a Assertion) Element() DictionaryElement {
+	value := a.Operator
+	if value != "" && a.Value != "" {
+		value += " "
+	}
+	value += a.Value
+	return DictionaryElement{a.Expr, value, !a.Enabled}
+}
+
+// Assertions reads the assertions of an assert block
+func (t *DictionaryBlock) Assertions() ([]Assertion, error) {
+	if err := checkBlockTag(t, "assert", ""); err != nil {
+		return nil, err
+	}
+	assertions := make([]Assertion, 0, len(t.Content))
+	for _, e := range t.Content {
+		a, err := ParseAssertion(e)
+		if err != nil {
+			return nil, err
+		}
+		assertions = append(assertions, a)
+	}
+	return assertions, nil
+}
+
+// NewAssertBlock returns the assert block holding the assertions
+func NewAssertBlock(assertions []Assertion) *DictionaryBlock {
+	block := &DictionaryBlock{Name: "assert"}
+	for _, a := range assertions {
+		block.Content = append(block.Content, a.Element())
+	}
+	return block
+}
+
+// Number returns the value of the assertion as a number, such as the 200 of eq 200.
+// It is parsed whatever the locale: a decimal comma such as 1,5 is an error naming the expression.
+func (a Assertion) Number() (float64, error) {
+	f, err := strconv.ParseFloat(a.Value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("assert %s: invalid number %q", a.Expr, a.Value)
+	}
+	return f, nil
+}
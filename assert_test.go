@@ -0,0 +1,59 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssertions(t *testing.T) {
+	simpleFile := `assert {
+  res.status: eq 200
+  res.body.name: contains foo bar
+  ~res.body.id: isNumber
+  res.body.email: matches ^[a-z]+@toto[.]com$
+  res.headers.etag: abcd
+}`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assertions, err := read[0].(*DictionaryBlock).Assertions()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []Assertion{
+		{"res.status", "eq", "200", true},
+		{"res.body.name", "contains", "foo bar", true},
+		{"res.body.id", "isNumber", "", false},
+		{"res.body.email", "matches", `^[a-z]+@toto[.]com$`, true},
+		{"res.headers.etag", "", "abcd", true},
+	}
+	if !reflect.DeepEqual(assertions, expected) {
+		t.Fatalf("unexpected assertions %v", assertions)
+	}
+	encoded, err := Write([]ContentBlock{NewAssertBlock(assertions)})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(encoded) != simpleFile {
+		t.Fatalf("unexpected encoding:\n%s", string(encoded))
+	}
+}
+
+func TestAssertionInvalidPattern(t *testing.T) {
+	if _, err := ParseAssertion(DictionaryElement{"res.body", "matches [a-", false}); err == nil || err.Error() != "assert res.body: invalid pattern: error parsing regexp: missing closing ]: `[a-`" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := ParseAssertion(DictionaryElement{"res.body", "notMatches ^[a-z]+$", false}); err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestAssertionNumber(t *testing.T) {
+	if n, err := (Assertion{"res.body.total", "gte", "1.5", true}).Number(); err != nil || n != 1.5 {
+		t.Fatalf("unexpected number %v, %v", n, err)
+	}
+	if _, err := (Assertion{"res.body.total", "gte", "1,5", true}).Number(); err == nil || err.Error() != `assert res.body.total: invalid number "1,5"` {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
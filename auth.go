@@ -0,0 +1,159 @@
+package bru
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// BasicAuth holds the credentials of an auth:basic block
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// BearerAuth holds the token of an auth:bearer block
+type BearerAuth struct {
+	Token string
+}
+
+// checkBlockTag returns an error if the block is not of the given name and type
+func checkBlockTag(block ContentBlock, name string, blockType string) error {
+	if block.GetName() != name || block.GetType() != blockType {
+		return fmt.Errorf("expected a %s:%s block, got %s", name, blockType, tagOf(block))
+	}
+	return nil
+}
+
+// tagOf returns the full tag of a block
+func tagOf(block ContentBlock) string {
+	if block.GetType() == "" {
+		return block.GetName()
+	}
+	return block.GetName() + ":" + block.GetType()
+}
+
+// BasicAuth reads the credentials of an auth:basic block
+func (t *DictionaryBlock) BasicAuth() (BasicAuth, error) {
+	if err := checkBlockTag(t, "auth", "basic"); err != nil {
+		return BasicAuth{}, err
+	}
+	username, _ := t.Get("username")
+	password, _ := t.Get("password")
+	return BasicAuth{username, password}, nil
+}
+
+// BearerAuth reads the token of an auth:bearer block
+func (t *DictionaryBlock) BearerAuth() (BearerAuth, error) {
+	if err := checkBlockTag(t, "auth", "bearer"); err != nil {
+		return BearerAuth{}, err
+	}
+	token, _ := t.Get("token")
+	return BearerAuth{token}, nil
+}
+
+// Block returns the auth:basic block holding the credentials
+func (a BasicAuth) Block() *DictionaryBlock {
+	return &DictionaryBlock{
+		Name: "auth",
+		Type: "basic",
+		Content: []DictionaryElement{
+			{"username", a.Username, false},
+			{"password", a.Password, false},
+		},
+	}
+}
+
+// Block returns the auth:bearer block holding the token
+func (a BearerAuth) Block() *DictionaryBlock {
+	return &DictionaryBlock{
+		Name:    "auth",
+		Type:    "bearer",
+		Content: []DictionaryElement{{"token", a.Token, false}},
+	}
+}
+
+// OAuth2Config holds the configuration of an auth:oauth2 block.
+// The keys used depend on the grant type, unused fields are left empty.
+type OAuth2Config struct {
+	GrantType        string
+	CallbackURL      string
+	AuthorizationURL string
+	AccessTokenURL   string
+	ClientID         string
+	ClientSecret     string
+	Scope            string
+	State            string
+	Username         string
+	Password         string
+	PKCE             bool
+}
+
+// oauth2Keys lists the keys written by Bruno for each grant type, in order
+var oauth2Keys = map[string][]string{
+	"password":           {"grant_type", "access_token_url", "username", "password", "client_id", "client_secret", "scope"},
+	"authorization_code": {"grant_type", "callback_url", "authorization_url", "access_token_url", "client_id", "client_secret", "scope", "state", "pkce"},
+	"client_credentials": {"grant_type", "access_token_url", "client_id", "client_secret", "scope"},
+}
+
+// fields maps the block keys to the config fields
+func (c *OAuth2Config) fields() map[string]*string {
+	return map[string]*string{
+		"grant_type":        &c.GrantType,
+		"callback_url":      &c.CallbackURL,
+		"authorization_url": &c.AuthorizationURL,
+		"access_token_url":  &c.AccessTokenURL,
+		"client_id":         &c.ClientID,
+		"client_secret":     &c.ClientSecret,
+		"scope":             &c.Scope,
+		"state":             &c.State,
+		"username":          &c.Username,
+		"password":          &c.Password,
+	}
+}
+
+// OAuth2Config reads the configuration of an auth:oauth2 block
+func (t *DictionaryBlock) OAuth2Config() (OAuth2Config, error) {
+	var c OAuth2Config
+	if err := checkBlockTag(t, "auth", "oauth2"); err != nil {
+		return c, err
+	}
+	for key, field := range c.fields() {
+		*field, _ = t.Get(key)
+	}
+	if pkce, ok := t.Get("pkce"); ok && pkce != "" {
+		var err error
+		if c.PKCE, err = strconv.ParseBool(pkce); err != nil {
+			return c, fmt.Errorf("invalid pkce value %q in auth:oauth2 block", pkce)
+		}
+	}
+	return c, nil
+}
+
+// Block returns the auth:oauth2 block holding the configuration.
+// For known grant types, the keys written are the ones Bruno writes,
+// otherwise every non-empty field is written.
+func (c OAuth2Config) Block() *DictionaryBlock {
+	fields := c.fields()
+	block := &DictionaryBlock{Name: "auth", Type: "oauth2"}
+	keys, known := oauth2Keys[c.GrantType]
+	if !known {
+		keys = []string{"grant_type", "callback_url", "authorization_url", "access_token_url", "username", "password",
+			"client_id", "client_secret", "scope", "state", "pkce"}
+	}
+	for _, key := range keys {
+		var value string
+		if key == "pkce" {
+			if !known && !c.PKCE {
+				continue
+			}
+			value = strconv.FormatBool(c.PKCE)
+		} else {
+			value = *fields[key]
+			if !known && value == "" {
+				continue
+			}
+		}
+		block.Content = append(block.Content, DictionaryElement{key, value, false})
+	}
+	return block
+}
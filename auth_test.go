@@ -0,0 +1,109 @@
+package bru
+
+import "testing"
+
+func TestAuthBlocks(t *testing.T) {
+	simpleFile := `auth {
+  mode: basic
+}
+
+auth:basic {
+  username: toto
+  password: {{password}}
+}
+
+auth:bearer {
+  token: abcd
+}`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	mode, _ := read[0].(*DictionaryBlock).Get("mode")
+	if mode != "basic" {
+		t.Fatalf("unexpected auth mode %q", mode)
+	}
+	basic, err := read[1].(*DictionaryBlock).BasicAuth()
+	if err != nil || basic != (BasicAuth{"toto", "{{password}}"}) {
+		t.Fatalf("unexpected basic auth %v: %v", basic, err)
+	}
+	bearer, err := read[2].(*DictionaryBlock).BearerAuth()
+	if err != nil || bearer != (BearerAuth{"abcd"}) {
+		t.Fatalf("unexpected bearer auth %v: %v", bearer, err)
+	}
+	if _, err := read[2].(*DictionaryBlock).BasicAuth(); err == nil {
+		t.Fatal("reading basic auth from a bearer block should have failed")
+	}
+	encoded, err := Write([]ContentBlock{read[0], basic.Block(), bearer.Block()})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(encoded) != simpleFile {
+		t.Fatalf("unexpected encoding:\n%s", string(encoded))
+	}
+}
+
+func TestOAuth2Block(t *testing.T) {
+	simpleFile := `auth:oauth2 {
+  grant_type: authorization_code
+  callback_url: http://localhost:8080/callback
+  authorization_url: https://auth.toto.com/authorize
+  access_token_url: https://auth.toto.com/token
+  client_id: {{clientId}}
+  client_secret: {{clientSecret}}
+  scope: read write
+  state: 
+  pkce: true
+}`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	config, err := read[0].(*DictionaryBlock).OAuth2Config()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if config.GrantType != "authorization_code" || config.ClientID != "{{clientId}}" || config.Scope != "read write" || !config.PKCE {
+		t.Fatalf("unexpected config %+v", config)
+	}
+	encoded, err := Write([]ContentBlock{config.Block()})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(encoded) != simpleFile {
+		t.Fatalf("unexpected encoding:\n%s", string(encoded))
+	}
+}
+
+func TestOtherAuthBlocks(t *testing.T) {
+	decodeAndEncodeFileWithDefault([]byte(`auth:awsv4 {
+  accessKeyId: {{accessKeyId}}
+  secretAccessKey: {{secretAccessKey}}
+  sessionToken: 
+  service: execute-api
+  region: eu-west-1
+  profileName: 
+}
+
+auth:digest {
+  username: toto
+  password: secret
+}
+
+auth:ntlm {
+  username: toto
+  password: secret
+  domain: CORP
+}
+
+auth:wsse {
+  username: toto
+  password: secret
+}
+
+auth:apikey {
+  key: X-Api-Key
+  value: {{apiKey}}
+  placement: header
+}`), t)
+}
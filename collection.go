@@ -0,0 +1,372 @@
+package bru
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Files of a collection directory which are not requests
+const (
+	collectionConfigFile = "bruno.json"
+	collectionFile       = "collection.bru"
+	folderFile           = "folder.bru"
+	environmentsDir      = "environments"
+)
+
+// A Collection is a Bruno collection: a tree of folders holding requests, read from a directory.
+type Collection struct {
+	// Path is the directory the collection was loaded from
+	Path string
+	// Config is the content of bruno.json, nil if there is none
+	Config json.RawMessage
+	// Blocks are the blocks of collection.bru, shared by all the requests, nil if there is none
+	Blocks []ContentBlock
+	// Environments are read from the environments directory
+	Environments []*Environment
+	// Folders and Requests are the content of the root directory
+	Folders  []*Folder
+	Requests []*Request
+	// failed are the paths of the files and directories which could not be loaded, left alone by Save
+	failed []string
+	// fsys is the filesystem the collection was loaded from or saved to, nil for a collection built in memory
+	fsys fs.FS
+}
+
+// A Folder is a directory of a collection
+type Folder struct {
+	Name string // name of the directory
+	Path string // path relative to the collection, with forward slashes
+	// Blocks are the blocks of folder.bru, shared by the requests of the folder, nil if there is none
+	Blocks   []ContentBlock
+	Folders  []*Folder
+	Requests []*Request
+}
+
+// A Request is a .bru file of a collection
+type Request struct {
+	Name   string // name from the meta block, the file name without extension if there is none
+	Path   string // path relative to the collection, with forward slashes
+	Blocks []ContentBlock
+}
+
+// httpMethods are the tags of the blocks holding the method and URL of a request
+var httpMethods = []string{"get", "post", "put", "delete", "patch", "options", "trace", "connect", "head"}
+
+// Method returns the HTTP method of the request in upper case, empty if it has no method block
+func (r *Request) Method() string {
+	for _, b := range r.Blocks {
+		for _, m := range httpMethods {
+			if tagOf(b) == m {
+				return strings.ToUpper(m)
+			}
+		}
+	}
+	return ""
+}
+
+// URL returns the URL of the request as written, with its variables
+func (r *Request) URL() string {
+	if method, ok := FindBlock(r.Blocks, strings.ToLower(r.Method())).(*DictionaryBlock); ok {
+		url, _ := method.Get("url")
+		return url
+	}
+	return ""
+}
+
+// Headers returns the enabled entries of the headers block of the request
+func (r *Request) Headers() []DictionaryElement {
+	var headers []DictionaryElement
+	for _, h := range dictionaryContent(FindBlock(r.Blocks, "headers")) {
+		if !h.Disabled {
+			headers = append(headers, h)
+		}
+	}
+	return headers
+}
+
+// BodyMode returns the body mode set in the method block of the request (e.g. json), empty if there is none
+func (r *Request) BodyMode() string {
+	if method, ok := FindBlock(r.Blocks, strings.ToLower(r.Method())).(*DictionaryBlock); ok {
+		if mode, _ := method.Get("body"); mode != "none" {
+			return mode
+		}
+	}
+	return ""
+}
+
+// LoadCollection reads the Bruno collection in the directory at path.
+// Every .bru file of the tree is read as a request, except collection.bru and folder.bru files
+// which hold the blocks shared by a collection or folder, and the files of the environments directory.
+// Hidden directories, node_modules and the paths of the ignore list of bruno.json are skipped.
+// The options are used to read every file.
+//
+// Symbolic links are never followed, so that a collection cannot read files outside of its directory nor loop:
+// a link which would be loaded, such as a .bru file or a directory, fails with ErrSymlink.
+// Manifest and VerifyManifest reject them as well.
+//
+// A file which cannot be read does not stop the loading: the collection of the other files is returned
+// along with a *LoadReport listing the errors of every file. Use WithFailFast to stop at the first error instead.
+func LoadCollection(path string, opts ...Option) (*Collection, error) {
+	l := newCollectionLoader(os.DirFS(path), opts)
+	l.read = func(name string) ([]ContentBlock, error) {
+		// Memory-mapped if possible
+		return ReadFile(filepath.Join(path, filepath.FromSlash(name)), opts...)
+	}
+	c, err := l.load()
+	if c != nil {
+		c.Path = path
+	}
+	return c, err
+}
+
+// LoadCollectionFS reads the Bruno collection at the root of fsys, such as an embed.FS or a zip.Reader,
+// the same way as LoadCollection. The Path of the returned collection is empty.
+func LoadCollectionFS(fsys fs.FS, opts ...Option) (*Collection, error) {
+	l := newCollectionLoader(fsys, opts)
+	l.read = func(name string) ([]ContentBlock, error) {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		return Read(data, opts...)
+	}
+	return l.load()
+}
+
+// ErrSymlink is the error of the symbolic links of a collection, which are not followed
+var ErrSymlink = errors.New("symbolic link not followed")
+
+// A FileError is the error of a file of a collection which could not be read
+type FileError struct {
+	Path string // path relative to the collection, with forward slashes
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// A LoadReport is returned by LoadCollection when some files of the collection could not be read
+type LoadReport struct {
+	// Collection holds the files which could be read
+	Collection *Collection
+	Errors     []*FileError
+}
+
+func (r *LoadReport) Error() string {
+	messages := make([]string, len(r.Errors))
+	for i, err := range r.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the errors of the files, for errors.Is and errors.As
+func (r *LoadReport) Unwrap() []error {
+	errs := make([]error, len(r.Errors))
+	for i, err := range r.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// collectionLoader reads a collection from a filesystem
+type collectionLoader struct {
+	fsys     fs.FS
+	read     func(name string) ([]ContentBlock, error) // reads the bru file at name in fsys
+	opts     []Option
+	failFast bool
+	sort     SortPolicy
+	ignore   []string
+	errors   []*FileError
+}
+
+func newCollectionLoader(fsys fs.FS, opts []Option) *collectionLoader {
+	o := newOptions(opts)
+	return &collectionLoader{fsys: fsys, opts: opts, failFast: o.FailFast, sort: o.Sort}
+}
+
+func (l *collectionLoader) load() (*Collection, error) {
+	c := &Collection{fsys: l.fsys}
+	config, err := l.readFile(collectionConfigFile)
+	if err == nil {
+		var parsed *CollectionConfig
+		if parsed, err = ParseCollectionConfig(config); err == nil {
+			c.Config, l.ignore = config, parsed.Ignore
+		}
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		if err := l.fail(collectionConfigFile, err); err != nil {
+			return nil, err
+		}
+	}
+	c.Blocks, err = l.readOptional(collectionFile)
+	if err != nil {
+		if err := l.fail(collectionFile, err); err != nil {
+			return nil, err
+		}
+	}
+	c.Environments, err = loadEnvironments(l.fsys, l.opts, l.fail)
+	if err != nil {
+		return nil, err
+	}
+	root := Folder{}
+	if err := l.loadFolder("", &root); err != nil {
+		return nil, err
+	}
+	root.Sort(l.sort)
+	c.Folders, c.Requests = root.Folders, root.Requests
+	if len(l.errors) > 0 {
+		for _, err := range l.errors {
+			c.failed = append(c.failed, err.Path)
+		}
+		return c, &LoadReport{c, l.errors}
+	}
+	return c, nil
+}
+
+// fail records the error of the file at name, returning it only when failing fast
+func (l *collectionLoader) fail(name string, err error) error {
+	fileErr := &FileError{name, err}
+	if l.failFast {
+		return fileErr
+	}
+	l.errors = append(l.errors, fileErr)
+	return nil
+}
+
+// loadFolder reads the folders and requests of the directory at rel in the collection into f
+func (l *collectionLoader) loadFolder(rel string, f *Folder) error {
+	dir := rel
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := fs.ReadDir(l.fsys, dir)
+	if err != nil {
+		if rel == "" {
+			// Nothing to load
+			return err
+		}
+		return l.fail(rel, err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		relPath := path.Join(rel, name)
+		if ignored(relPath, l.ignore) {
+			continue
+		}
+		isDir, link := e.IsDir(), e.Type()&fs.ModeSymlink != 0
+		if link {
+			// Not followed, but reported if it would be loaded
+			info, err := fs.Stat(l.fsys, relPath)
+			isDir = err == nil && info.IsDir()
+		}
+		if isDir {
+			if strings.HasPrefix(name, ".") || name == "node_modules" || rel == "" && name == environmentsDir {
+				continue
+			}
+			if link {
+				if err := l.fail(relPath, ErrSymlink); err != nil {
+					return err
+				}
+				continue
+			}
+			sub := &Folder{Name: name, Path: relPath}
+			sub.Blocks, err = l.readOptional(path.Join(relPath, folderFile))
+			if err != nil {
+				if err := l.fail(path.Join(relPath, folderFile), err); err != nil {
+					return err
+				}
+			}
+			if err := l.loadFolder(relPath, sub); err != nil {
+				return err
+			}
+			f.Folders = append(f.Folders, sub)
+			continue
+		}
+		if path.Ext(name) != ".bru" || name == folderFile || rel == "" && name == collectionFile {
+			continue
+		}
+		if link {
+			if err := l.fail(relPath, ErrSymlink); err != nil {
+				return err
+			}
+			continue
+		}
+		blocks, err := l.read(relPath)
+		if err != nil {
+			if err := l.fail(relPath, err); err != nil {
+				return err
+			}
+			continue
+		}
+		f.Requests = append(f.Requests, newRequest(relPath, blocks))
+	}
+	return nil
+}
+
+// failedToLoad reports whether the file at rel, or one of its directories, could not be loaded
+func (c *Collection) failedToLoad(rel string) bool {
+	for _, p := range c.failed {
+		if rel == p || strings.HasPrefix(rel, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// newRequest returns the request of the file at path, named after its meta block
+func newRequest(p string, blocks []ContentBlock) *Request {
+	r := &Request{Path: p, Blocks: blocks}
+	if meta, ok := FindBlock(blocks, "meta").(*DictionaryBlock); ok {
+		r.Name, _ = meta.Get("name")
+	}
+	if r.Name == "" {
+		r.Name = strings.TrimSuffix(path.Base(p), ".bru")
+	}
+	return r
+}
+
+// readOptional reads the bru file at name, returning no blocks if it does not exist
+func (l *collectionLoader) readOptional(name string) ([]ContentBlock, error) {
+	if isSymlink(l.fsys, name) {
+		return nil, ErrSymlink
+	}
+	blocks, err := l.read(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return blocks, err
+}
+
+// readFile reads the file at name, which must not be a symbolic link
+func (l *collectionLoader) readFile(name string) ([]byte, error) {
+	if isSymlink(l.fsys, name) {
+		return nil, ErrSymlink
+	}
+	return fs.ReadFile(l.fsys, name)
+}
+
+// isSymlink reports whether the file at name in fsys is a symbolic link.
+// fs.FS has no Lstat, so the entry is looked up in its directory.
+func isSymlink(fsys fs.FS, name string) bool {
+	entries, err := fs.ReadDir(fsys, path.Dir(name))
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if e.Name() == path.Base(name) {
+			return e.Type()&fs.ModeSymlink != 0
+		}
+	}
+	return false
+}
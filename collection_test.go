@@ -0,0 +1,163 @@
+package bru
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadCollection(t *testing.T) {
+	c, err := LoadCollection("testFiles")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(c.Config) == 0 || len(c.Blocks) != 2 || len(c.Requests) != 0 {
+		t.Fatalf("unexpected collection %+v", c)
+	}
+	if len(c.Folders) != 2 || c.Folders[0].Name != "Repository" || c.Folders[1].Name != "User" {
+		t.Fatalf("unexpected folders %+v", c.Folders)
+	}
+	user := c.Folders[1]
+	if len(user.Blocks) != 2 || len(user.Requests) != 2 {
+		t.Fatalf("unexpected user folder %+v", user)
+	}
+	info := user.Requests[0]
+	if info.Name != "User Info" || info.Path != "User/User Info.bru" {
+		t.Fatalf("unexpected request %+v", info)
+	}
+	if info.Method() != "GET" || info.URL() != "{{baseUrl}}/users/usebruno" {
+		t.Fatalf("unexpected method %s or url %s", info.Method(), info.URL())
+	}
+}
+
+func TestLoadCollectionErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "Folder"), 0o755); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Folder", "Broken.bru"), []byte("meta {\n  name"), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Folder", "Valid.bru"), []byte("get {\n  url: /\n}"), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	c, err := LoadCollection(dir)
+	var report *LoadReport
+	if !errors.As(err, &report) {
+		t.Fatalf("expected a load report, got %v", err)
+	}
+	if report.Collection != c || len(report.Errors) != 1 || report.Errors[0].Path != "Folder/Broken.bru" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if len(c.Folders) != 1 || len(c.Folders[0].Requests) != 1 || c.Folders[0].Requests[0].Path != "Folder/Valid.bru" {
+		t.Fatalf("the valid request should be loaded: %+v", c.Folders)
+	}
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || !strings.HasPrefix(err.Error(), "Folder/Broken.bru: ") {
+		t.Fatalf("error should name the file: %s", err)
+	}
+	c, err = LoadCollection(dir, WithFailFast())
+	if c != nil || err == nil || errors.As(err, &report) || !strings.HasPrefix(err.Error(), "Folder/Broken.bru: ") {
+		t.Fatalf("unexpected fail fast result %v, %v", c, err)
+	}
+	if _, err := LoadCollection(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("should have failed")
+	}
+}
+
+func TestLoadCollectionSymlinks(t *testing.T) {
+	dir, outside := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "Outside.bru"), []byte("get {\n  url: /outside\n}\n"), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Valid.bru"), []byte("get {\n  url: /\n}\n"), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	links := map[string]string{
+		"Linked.bru":     filepath.Join(outside, "Outside.bru"),
+		"Loop":           dir,
+		"collection.bru": filepath.Join(outside, "Outside.bru"),
+		"notes.txt":      filepath.Join(outside, "Outside.bru"),
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dir, name)); err != nil {
+			t.Skip("symbolic links not supported: " + err.Error())
+		}
+	}
+	c, err := LoadCollection(dir)
+	var report *LoadReport
+	if !errors.As(err, &report) {
+		t.Fatalf("expected a load report, got %v", err)
+	}
+	var failed []string
+	for _, e := range report.Errors {
+		if !errors.Is(e, ErrSymlink) {
+			t.Fatalf("unexpected error %v", e)
+		}
+		failed = append(failed, e.Path)
+	}
+	if expected := []string{"collection.bru", "Linked.bru", "Loop"}; !reflect.DeepEqual(failed, expected) {
+		t.Fatalf("unexpected errors %v", report.Errors)
+	}
+	if c.Blocks != nil || len(c.Folders) != 0 || len(c.Requests) != 1 || c.Requests[0].Path != "Valid.bru" {
+		t.Fatalf("links should not be followed: %+v", c)
+	}
+	// The links are left alone
+	if _, err := c.Save(dir); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "Linked.bru")); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := LoadCollection(dir, WithFailFast()); !errors.Is(err, ErrSymlink) {
+		t.Fatalf("unexpected fail fast error %v", err)
+	}
+}
+
+func TestLoadCollectionFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"bruno.json":                  {Data: []byte(`{"version": "1", "name": "Mem", "type": "collection"}`)},
+		"collection.bru":              {Data: []byte("headers {\n  Accept: application/json\n}")},
+		"environments/Local.bru":      {Data: []byte("vars {\n  baseUrl: http://localhost\n}")},
+		"Users/folder.bru":            {Data: []byte("meta {\n  name: Users\n}")},
+		"Users/Get User.bru":          {Data: []byte("get {\n  url: {{baseUrl}}/users/1\n}")},
+		"Users/Admins/List.bru":       {Data: []byte("meta {\n  name: List admins\n}")},
+		"Users/Admins/notes.txt":      {Data: []byte("not a request")},
+		".git/HEAD.bru":               {Data: []byte("not read")},
+		"node_modules/lib/module.bru": {Data: []byte("not read")},
+	}
+	c, err := LoadCollectionFS(fsys)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if c.Path != "" || len(c.Config) == 0 || len(c.Blocks) != 1 || len(c.Environments) != 1 || len(c.Requests) != 0 {
+		t.Fatalf("unexpected collection %+v", c)
+	}
+	if len(c.Folders) != 1 || len(c.Folders[0].Blocks) != 1 || len(c.Folders[0].Requests) != 1 {
+		t.Fatalf("unexpected folders %+v", c.Folders)
+	}
+	admins := c.Folders[0].Folders[0]
+	if admins.Path != "Users/Admins" || admins.Blocks != nil || len(admins.Requests) != 1 || admins.Requests[0].Name != "List admins" {
+		t.Fatalf("unexpected sub folder %+v", admins)
+	}
+	if c.Folders[0].Requests[0].URL() != "{{baseUrl}}/users/1" {
+		t.Fatalf("unexpected request %+v", c.Folders[0].Requests[0])
+	}
+
+	fromDisk, err := LoadCollection("testFiles")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	fromFS, err := LoadCollectionFS(os.DirFS("testFiles"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	fromFS.Path = fromDisk.Path
+	if !reflect.DeepEqual(fromDisk, fromFS) {
+		t.Fatal("collection read differently from fs.FS")
+	}
+}
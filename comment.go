@@ -0,0 +1,96 @@
+package bru
+
+import "strings"
+
+// A Comment is a # or // comment line of a bru file.
+// Comments are allowed between blocks and between the entries of dictionary and array blocks,
+// text blocks content is always kept as is.
+type Comment struct {
+	Text   string // text of the comment, including its # or // marker
+	Offset int64  // offset of the marker in the input
+	Line   int64  // line of the comment, starting at 1
+	// Entry is the index of the entry the comment comes before inside its block,
+	// the number of entries for a comment before the closing delimiter,
+	// or one of CommentBeforeBlock and CommentAfterBlock for comments outside of the block.
+	Entry int
+}
+
+// Positions of the comments found outside of blocks
+const (
+	CommentBeforeBlock = -1 // on the lines before the block
+	CommentAfterBlock  = -2 // after the last block of the file
+)
+
+// commentsOf returns the comments found by the scanner in data
+func commentsOf(data []byte, scanned []scannedComment) []Comment {
+	comments := make([]Comment, 0, len(scanned))
+	for _, c := range scanned {
+		text := strings.TrimRight(string(data[c.start:c.end]), "\r")
+		comments = append(comments, Comment{text, c.start, c.line, CommentBeforeBlock})
+	}
+	return comments
+}
+
+// blockSpan is the position of a decoded block in the input
+type blockSpan struct {
+	start, end int   // offsets of the tag and after the closing delimiter
+	entries    []int // offsets of the entries
+}
+
+// attachComments attaches every comment to the block enclosing it, or to the block following it.
+// Comments after the last block are attached to it.
+func attachComments(blocks []ContentBlock, spans []blockSpan, comments []Comment) {
+	if len(blocks) == 0 {
+		return
+	}
+	b := 0
+	for _, c := range comments {
+		for b < len(blocks)-1 && int(c.Offset) >= spans[b].end {
+			b++
+		}
+		span := spans[b]
+		switch {
+		case int(c.Offset) < span.start:
+			c.Entry = CommentBeforeBlock
+		case int(c.Offset) >= span.end:
+			c.Entry = CommentAfterBlock
+		default:
+			c.Entry = 0
+			for _, e := range span.entries {
+				if e < int(c.Offset) {
+					c.Entry++
+				}
+			}
+		}
+		setBlockComments(blocks[b], append(blockComments(blocks[b]), c))
+	}
+}
+
+// blockComments returns the comments attached to a block
+func blockComments(block ContentBlock) []Comment {
+	switch b := block.(type) {
+	case *DictionaryBlock:
+		return b.Comments
+	case *TextBlock:
+		return b.Comments
+	case *ArrayBlock:
+		return b.Comments
+	case *GenericBlock:
+		return b.Comments
+	}
+	return nil
+}
+
+// setBlockComments replaces the comments attached to a block
+func setBlockComments(block ContentBlock, comments []Comment) {
+	switch b := block.(type) {
+	case *DictionaryBlock:
+		b.Comments = comments
+	case *TextBlock:
+		b.Comments = comments
+	case *ArrayBlock:
+		b.Comments = comments
+	case *GenericBlock:
+		b.Comments = comments
+	}
+}
@@ -0,0 +1,170 @@
+package bru
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestComments(t *testing.T) {
+	simpleFile := `# Fetches the user
+meta {
+  name: User Info
+  # seq: 2
+  seq: 1
+}
+
+// Secrets
+vars:secret [
+  # first
+  access_key,
+  access_secret
+  // last
+]
+
+headers {
+  # only a comment
+}
+# end`
+	var comments []Comment
+	read, err := Read([]byte(simpleFile), WithComments(&comments))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	meta := read[0].(*DictionaryBlock).Content
+	if !reflect.DeepEqual(meta, []DictionaryElement{{"name", "User Info", false}, {"seq", "1", false}}) {
+		t.Fatalf("unexpected meta %v", meta)
+	}
+	if vars := read[1].(*ArrayBlock).Content; len(vars) != 2 || vars[1].Value != "access_secret" {
+		t.Fatalf("unexpected vars %v", vars)
+	}
+	if headers := read[2].(*DictionaryBlock).Content; len(headers) != 0 {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+	expected := []Comment{
+		{"# Fetches the user", 0, 1, CommentBeforeBlock},
+		{"# seq: 2", 46, 4, 1},
+		{"// Secrets", 67, 8, CommentBeforeBlock},
+		{"# first", 94, 10, 0},
+		{"// last", 134, 13, 2},
+		{"# only a comment", 157, 17, 0},
+		{"# end", 176, 19, CommentAfterBlock},
+	}
+	if !reflect.DeepEqual(comments, expected) {
+		t.Fatalf("unexpected comments %v", comments)
+	}
+}
+
+func TestCommentsRoundTrip(t *testing.T) {
+	simpleFile := `# Fetches the user
+meta {
+  name: User Info
+  # seq: 2
+  seq: 1
+}
+
+// Secrets
+vars:secret [
+  # first
+  access_key,
+  access_secret
+  // last
+]
+
+headers {
+  # only a comment
+}
+
+# The body
+body:json {
+  {
+    # not a comment
+  }
+}
+# end`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if comments := read[3].(*TextBlock).Comments; len(comments) != 2 || comments[0].Text != "# The body" {
+		t.Fatalf("unexpected body comments %v", comments)
+	}
+	written, err := Write(read)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(written) != simpleFile {
+		t.Fatalf("comments were not kept:\n%s", written)
+	}
+}
+
+func TestCommentsWrite(t *testing.T) {
+	block := &DictionaryBlock{
+		Name:    "headers",
+		Content: []DictionaryElement{{"accept", "json", false}},
+		Comments: []Comment{
+			{Text: "# Headers", Entry: CommentBeforeBlock},
+			{Text: "# last", Entry: 1},
+		},
+	}
+	written, err := Write([]ContentBlock{block})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(written) != "# Headers\nheaders {\n  accept: json\n  # last\n}" {
+		t.Fatalf("unexpected output %q", written)
+	}
+}
+
+func TestCommentInvalidSlash(t *testing.T) {
+	if _, err := Read([]byte("/ toto\nmeta {\n}")); err == nil {
+		t.Fatal("should have failed")
+	}
+}
+
+func TestSlashEntries(t *testing.T) {
+	simpleFile := `query {
+  /path: a
+  // note
+  ~/other: b
+  /: c
+}
+
+vars:secret [
+  /a,
+  // note
+  /
+]`
+	var comments []Comment
+	read, err := Read([]byte(simpleFile), WithComments(&comments))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	query := read[0].(*DictionaryBlock).Content
+	if !reflect.DeepEqual(query, []DictionaryElement{{"/path", "a", false}, {"/other", "b", true}, {"/", "c", false}}) {
+		t.Fatalf("unexpected query %v", query)
+	}
+	if vars := read[1].(*ArrayBlock).Content; !reflect.DeepEqual(vars, []ArrayElement{{"/a", false}, {"/", false}}) {
+		t.Fatalf("unexpected vars %v", vars)
+	}
+	if len(comments) != 2 || comments[0].Text != "// note" || comments[0].Entry != 1 || comments[1].Entry != 1 {
+		t.Fatalf("unexpected comments %v", comments)
+	}
+	var values []string
+	tokenizer := NewTokenizer([]byte(simpleFile))
+	for {
+		token, err := tokenizer.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if token.Kind == Key || token.Kind == Value {
+			values = append(values, token.Value)
+		}
+	}
+	if !reflect.DeepEqual(values, []string{"/path", "a", "~/other", "b", "/", "c", "/a", "/"}) {
+		t.Fatalf("unexpected tokens %v", values)
+	}
+}
@@ -0,0 +1,109 @@
+package bru
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CollectionConfig is the content of the bruno.json file of a collection
+type CollectionConfig struct {
+	Version string   `json:"version"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Ignore  []string `json:"ignore,omitempty"` // paths relative to the collection skipped when loading it
+	Presets *Presets `json:"presets,omitempty"`
+	// Other holds the members not known by this package, kept to be written back
+	Other map[string]json.RawMessage `json:"-"`
+}
+
+// Presets are the defaults of the requests created in a collection
+type Presets struct {
+	RequestType string `json:"requestType"`
+	RequestURL  string `json:"requestUrl"`
+}
+
+// NewCollectionConfig returns the configuration the Bruno app creates for a new collection
+func NewCollectionConfig(name string) *CollectionConfig {
+	return &CollectionConfig{
+		Version: "1",
+		Name:    name,
+		Type:    "collection",
+		Ignore:  []string{"node_modules", ".git"},
+	}
+}
+
+// ParseCollectionConfig reads the content of a bruno.json file
+func ParseCollectionConfig(data []byte) (*CollectionConfig, error) {
+	c := &CollectionConfig{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Encode returns the content of the bruno.json file, indented as the Bruno app does
+func (c *CollectionConfig) Encode() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// collectionConfigMembers are the members of bruno.json read in CollectionConfig fields
+var collectionConfigMembers = []string{"version", "name", "type", "ignore", "presets"}
+
+func (c *CollectionConfig) UnmarshalJSON(data []byte) error {
+	type plain CollectionConfig
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for _, m := range collectionConfigMembers {
+		delete(members, m)
+	}
+	c.Other = nil
+	if len(members) > 0 {
+		c.Other = members
+	}
+	return nil
+}
+
+func (c CollectionConfig) MarshalJSON() ([]byte, error) {
+	type plain CollectionConfig
+	known, err := json.Marshal(plain(c))
+	if err != nil || len(c.Other) == 0 {
+		return known, err
+	}
+	other, err := json.Marshal(c.Other)
+	if err != nil {
+		return nil, err
+	}
+	// Other members follow the known ones, sorted by name
+	known = bytes.TrimSuffix(known, []byte("}"))
+	return append(append(known, ','), other[1:]...), nil
+}
+
+// ignore returns the ignore list of the bruno.json of the collection
+func (c *Collection) ignore() ([]string, error) {
+	if c.Config == nil {
+		return nil, nil
+	}
+	config, err := ParseCollectionConfig(c.Config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", collectionConfigFile, err)
+	}
+	return config.Ignore, nil
+}
+
+// ignored reports whether the path rel of a collection is in its ignore list
+func ignored(rel string, ignore []string) bool {
+	for _, i := range ignore {
+		i = strings.Trim(i, "/")
+		if rel == i || strings.HasPrefix(rel, i+"/") {
+			return true
+		}
+	}
+	return false
+}
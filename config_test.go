@@ -0,0 +1,90 @@
+package bru
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCollectionConfig(t *testing.T) {
+	data := []byte(`{
+  "version": "1",
+  "name": "Github",
+  "type": "collection",
+  "ignore": [
+    "node_modules",
+    ".git"
+  ],
+  "presets": {
+    "requestType": "http",
+    "requestUrl": "https://api.github.com"
+  },
+  "scripts": {
+    "moduleWhitelist": [
+      "crypto"
+    ]
+  }
+}`)
+	config, err := ParseCollectionConfig(data)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if config.Name != "Github" || config.Presets.RequestURL != "https://api.github.com" || !reflect.DeepEqual(config.Ignore, []string{"node_modules", ".git"}) {
+		t.Fatalf("unexpected config %+v", config)
+	}
+	if len(config.Other) != 1 || config.Other["scripts"] == nil {
+		t.Fatalf("unexpected other members %v", config.Other)
+	}
+	encoded, err := config.Encode()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(encoded) != string(data) {
+		t.Fatalf("unexpected encoding:\n%s", encoded)
+	}
+	encoded, err = NewCollectionConfig("New").Encode()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(encoded) != "{\n  \"version\": \"1\",\n  \"name\": \"New\",\n  \"type\": \"collection\",\n  \"ignore\": [\n    \"node_modules\",\n    \".git\"\n  ]\n}" {
+		t.Fatalf("unexpected encoding:\n%s", encoded)
+	}
+}
+
+func TestCollectionIgnore(t *testing.T) {
+	dir := t.TempDir()
+	config, _ := NewCollectionConfig("Ignore").Encode()
+	files := map[string]string{
+		"bruno.json":         `{"version": "1", "name": "Ignore", "type": "collection", "ignore": ["Drafts", "Old.bru"]}`,
+		"Drafts/Broken.bru":  "meta {",
+		"Old.bru":            "meta {",
+		"Kept.bru":           "get {\n  url: https://toto.com\n}\n",
+		"Other/bruno.json":   string(config),
+		"Other/Request.bru":  "get {\n  url: https://toto.com\n}\n",
+		"Drafts/sub/Bad.bru": "}",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err.Error())
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	c, err := LoadCollection(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(c.Requests) != 1 || len(c.Folders) != 1 || c.Folders[0].Name != "Other" {
+		t.Fatalf("unexpected collection %+v", c)
+	}
+	changes, err := c.Save(dir, WithDryRun())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(changes) != 0 {
+		t.Fatalf("ignored files should be kept, got %v", changes)
+	}
+}
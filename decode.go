@@ -1,21 +1,133 @@
 package bru
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 )
 
-func Read(data []byte) ([]ContentBlock, error) {
+// Read decodes the blocks of a bru file.
+// CRLF line endings and a leading UTF-8 byte order mark are accepted,
+// offsets are then those of the input with LF line endings and without the mark.
+func Read(data []byte, opts ...Option) ([]ContentBlock, error) {
+	data = normalizeInput(data)
 	// Check for well-formedness.
 	// Avoids filling out half a data structure
 	// before discovering a JSON syntax error.
 	var d decodeState
-	err := checkValid(data, &d.scan)
+	d.opts = newOptions(opts)
+	d.scan.lenient = d.opts.LenientTags
+	d.scan.captureComments = true
+	check := checkValid
+	if d.opts.AllErrors {
+		check = checkValidAll
+	}
+	err := check(data, &d.scan)
+	d.scan.captureComments = false
+	comments := commentsOf(data, d.scan.comments)
+	if d.opts.Comments != nil {
+		*d.opts.Comments = comments
+	}
 	if err != nil {
 		return nil, err
 	}
 	d.init(data)
-	return d.unmarshal()
+	blocks, err := d.unmarshal()
+	if err != nil {
+		return nil, err
+	}
+	// Positions are only known once decoded
+	attachComments(blocks, d.spans, comments)
+	if d.opts.Comments != nil {
+		*d.opts.Comments = comments[:0]
+		for _, b := range blocks {
+			*d.opts.Comments = append(*d.opts.Comments, blockComments(b)...)
+		}
+	}
+	return blocks, nil
+}
+
+// ReadPartial decodes every well-formed block of data, skipping the malformed ones.
+// After a malformed block, decoding resumes at the next line starting with a tag.
+// It returns the decoded blocks along with the errors that made blocks be skipped,
+// positions being those of data.
+func ReadPartial(data []byte, opts ...Option) ([]ContentBlock, []error) {
+	data = normalizeInput(data)
+	var blocks []ContentBlock
+	var decodeErrs []error
+	scan := scanner{lenient: newOptions(opts).LenientTags}
+	prevEnd := 0
+	errs := scanBlocks(data, &scan, func(start, end int) {
+		// The comments before the block are read with it
+		start = leadingComments(data, start, prevEnd)
+		prevEnd = end
+		// Positions are relative to the segment
+		line := int64(bytes.Count(data[:start], []byte("\n")))
+		column := int64(start - bytes.LastIndexByte(data[:start], '\n') - 1)
+		read, err := Read(data[start:end], opts...)
+		if err != nil {
+			shiftSyntaxErrors(err, int64(start), line, column)
+			decodeErrs = append(decodeErrs, err)
+			return
+		}
+		for _, b := range read {
+			comments := blockComments(b)
+			for i := range comments {
+				comments[i].Offset += int64(start)
+				comments[i].Line += line
+			}
+		}
+		blocks = append(blocks, read...)
+	})
+	return blocks, append(errs, decodeErrs...)
+}
+
+// leadingComments returns the offset of the first of the comment lines right before the tag at start,
+// not going before min. It returns start if there are none.
+func leadingComments(data []byte, start, min int) int {
+	lineStart := bytes.LastIndexByte(data[:start], '\n') + 1
+	if len(bytes.TrimSpace(data[lineStart:start])) > 0 {
+		return start
+	}
+	first := start
+	for lineStart > min {
+		prev := bytes.LastIndexByte(data[:lineStart-1], '\n') + 1
+		if prev < min {
+			break
+		}
+		line := bytes.TrimSpace(data[prev : lineStart-1])
+		if len(line) > 0 && !bytes.HasPrefix(line, []byte("#")) && !bytes.HasPrefix(line, []byte("//")) {
+			break
+		}
+		if len(line) > 0 {
+			first = prev
+		}
+		lineStart = prev
+	}
+	return first
+}
+
+// shiftSyntaxErrors moves the syntax errors of err, found in a segment starting at offset,
+// after line lines and column bytes of its first line, to their position in the whole input
+func shiftSyntaxErrors(err error, offset, line, column int64) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			shiftSyntaxErrors(e, offset, line, column)
+		}
+		return
+	}
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return
+	}
+	syntaxErr.Offset += offset
+	if syntaxErr.Line == 1 {
+		syntaxErr.Column += column
+	}
+	if syntaxErr.Line > 0 {
+		syntaxErr.Line += line
+	}
 }
 
 func (d *decodeState) unmarshal() ([]ContentBlock, error) {
@@ -33,6 +145,8 @@ type decodeState struct {
 	off    int // next read offset in data
 	opcode int // last read result
 	scan   scanner
+	opts   Options
+	spans  []blockSpan // positions of the decoded blocks
 }
 
 // readIndex returns the position of the last byte read.
@@ -43,15 +157,21 @@ func (d *decodeState) readIndex() int {
 func (d *decodeState) init(data []byte) *decodeState {
 	d.data = data
 	d.off = 0
+	d.scan.bytes, d.scan.lines, d.scan.lineStart = 0, 0, 0
 	return d
 }
 
+// textPosition returns the position of the last byte read, lines being counted by the scanner
+func (d *decodeState) textPosition() textPosition {
+	return textPosition{line: d.scan.lines + 1, column: d.scan.bytes - d.scan.lineStart}
+}
+
 // skip scans to the end of what was started.
 func (d *decodeState) skip() {
 	s, data, i := &d.scan, d.data, d.off
 	depth := len(s.parseState)
 	for {
-		op := s.step(s, data[i])
+		op := s.stepByte(data[i])
 		i++
 		if len(s.parseState) < depth {
 			d.off = i
@@ -64,7 +184,7 @@ func (d *decodeState) skip() {
 // scanNext processes the byte at d.data[d.off].
 func (d *decodeState) scanNext() {
 	if d.off < len(d.data) {
-		d.opcode = d.scan.step(&d.scan, d.data[d.off])
+		d.opcode = d.scan.stepByte(d.data[d.off])
 		d.off++
 	} else {
 		d.opcode = d.scan.eof()
@@ -77,7 +197,7 @@ func (d *decodeState) scanNext() {
 func (d *decodeState) scanWhile(op int) {
 	s, data, i := &d.scan, d.data, d.off
 	for i < len(data) {
-		newOp := s.step(s, data[i])
+		newOp := s.stepByte(data[i])
 		i++
 		if newOp != op {
 			d.opcode = newOp
@@ -110,31 +230,37 @@ func (d *decodeState) value() ([]ContentBlock, error) {
 	return blocks, nil
 }
 
+// cutDisabledPrefix removes the '~' marking a disabled entry, reporting whether the entry is disabled
+func cutDisabledPrefix(s string) (string, bool) {
+	if strings.HasPrefix(s, "~") {
+		return s[1:], true
+	}
+	return s, false
+}
+
 func getBlockForTag(tag string) (ContentBlock, error) {
+	kind, ok := LookupTag(tag)
+	if !ok {
+		return nil, fmt.Errorf("could not find block for tag '%s'", tag)
+	}
 	// Split
-	for i, t := range tags {
-		if tag == t {
-			tag, tagData, _ := strings.Cut(tag, ":")
-			switch blockTypes[i] {
-			case dictionaryBlock:
-				return &DictionaryBlock{
-					Name: tag,
-					Type: tagData,
-				}, nil
-			case textBlock:
-				return &TextBlock{
-					Name: tag,
-					Type: tagData,
-				}, nil
-			case arrayBlock:
-				return &ArrayBlock{
-					Name: tag,
-					Type: tagData,
-				}, nil
-			}
-		}
+	name, tagData, _ := strings.Cut(tag, ":")
+	switch kind {
+	case DictionaryKind:
+		return &DictionaryBlock{
+			Name: name,
+			Type: tagData,
+		}, nil
+	case TextKind:
+		return &TextBlock{
+			Name: name,
+			Type: tagData,
+		}, nil
 	}
-	return nil, fmt.Errorf("could not find block for tag '%s'", tag)
+	return &ArrayBlock{
+		Name: name,
+		Type: tagData,
+	}, nil
 }
 
 // block consumes an block from d.data[d.off-1:], decoding into v.
@@ -148,6 +274,9 @@ func (d *decodeState) block() (ContentBlock, error) {
 		d.scanNext()
 	}
 	start := d.readIndex()
+	d.spans = append(d.spans, blockSpan{start: start})
+	span := &d.spans[len(d.spans)-1]
+	defer func() { span.end = d.off }()
 	for {
 		if d.opcode == scanEndTag {
 			break
@@ -157,8 +286,14 @@ func (d *decodeState) block() (ContentBlock, error) {
 	blockName := string(d.data[start:d.readIndex()])
 	block, err := getBlockForTag(blockName)
 	if err != nil {
-		return nil, err
+		if !d.opts.LenientTags {
+			return nil, err
+		}
+		name, tagData, _ := strings.Cut(blockName, ":")
+		block = &GenericBlock{Name: name, Type: tagData}
 	}
+	var positions blockPositions
+	defer func() { setBlockPositions(block, positions) }()
 	d.scanWhile(scanSkipSpace)
 
 	// Get the type of data to read
@@ -170,34 +305,76 @@ func (d *decodeState) block() (ContentBlock, error) {
 				break
 			}
 			d.scanWhile(scanSkipSpace)
+			if d.opcode == scanEndBlock {
+				// Empty dictionary, or only comments left
+				break
+			}
 			// Get the key
 			start := d.readIndex()
+			keyPos := d.textPosition()
 			d.scanWhile(scanContinue)
+			if d.opcode == scanEntryComment {
+				continue
+			}
+			span.entries = append(span.entries, start)
 			key := string(d.data[start:d.readIndex()])
 			d.scanWhile(scanSkipSpace)
 			value := ""
+			valuePos := keyPos
 			if d.opcode != scanDictionaryKey {
 				// Get the value
 				start = d.readIndex()
+				valuePos = d.textPosition()
 				d.scanWhile(scanContinue)
-				value = string(d.data[start:d.readIndex()])
+				raw := string(d.data[start:d.readIndex()])
+				if value = unquoteMultiline(raw); value != raw {
+					// The value starts on the line after the opening quote
+					valuePos.line++
+					valuePos.indent = int64(multilineIndent(raw))
+					valuePos.column = valuePos.indent + 1
+				}
+			}
+			key, disabled := cutDisabledPrefix(key)
+			if disabled {
+				keyPos.column++
 			}
-			dic = append(dic, DictionaryElement{key, value})
+			if unquoted := unquoteKey(key); unquoted != key {
+				key = unquoted
+				keyPos.column++
+			}
+			keyPos.text, valuePos.text = key, value
+			positions.keys = append(positions.keys, keyPos)
+			positions.values = append(positions.values, valuePos)
+			dic = append(dic, DictionaryElement{key, value, disabled})
 			d.scanNext()
 		}
 		return block, block.SetContent(dic)
 	case scanBeginArray:
-		dic := make([]string, 0)
+		dic := make([]ArrayElement, 0)
 		for {
 			if d.opcode == scanEndArray {
 				break
 			}
 			d.scanWhile(scanSkipSpace)
+			if d.opcode == scanEndArray {
+				// Empty array or trailing comma
+				break
+			}
 			// Get the value
 			start = d.readIndex()
+			pos := d.textPosition()
 			d.scanWhile(scanContinue)
-			value := string(d.data[start:d.readIndex()])
-			dic = append(dic, value)
+			if d.opcode == scanEntryComment {
+				continue
+			}
+			span.entries = append(span.entries, start)
+			value, disabled := cutDisabledPrefix(string(d.data[start:d.readIndex()]))
+			if disabled {
+				pos.column++
+			}
+			pos.text = value
+			positions.values = append(positions.values, pos)
+			dic = append(dic, ArrayElement{value, disabled})
 			d.scanNext()
 		}
 		return block, block.SetContent(dic)
@@ -209,7 +386,18 @@ func (d *decodeState) block() (ContentBlock, error) {
 			}
 			d.scanNext()
 		}
-		dic := ""
+		pos := d.textPosition()
+		if d.opcode == scanTextLine && d.data[d.readIndex()] == '\n' {
+			// Empty first line, already counted
+			pos.line--
+		}
+		pos.column = 1
+		w := textWriter{opts: d.opts}
+		generic, isGeneric := block.(*GenericBlock)
+		if isGeneric {
+			// The content is needed in memory to infer its kind
+			w.opts.SpillThreshold = 0
+		}
 		for {
 			if d.opcode == scanEndBlock {
 				break
@@ -217,11 +405,27 @@ func (d *decodeState) block() (ContentBlock, error) {
 			// Get the value
 			start = d.readIndex()
 			d.scanWhile(scanContinue)
-			value := string(d.data[start:d.readIndex()])
-			dic = dic + value + "\n"
+			if err := w.writeLine(d.data[start:d.readIndex()]); err != nil {
+				return nil, err
+			}
 			d.scanNext()
 		}
-		return block, block.SetContent(dic[:len(dic)-1])
+		content, spill, err := w.finish()
+		if err != nil {
+			return nil, err
+		}
+		// Empty for spilled content, read back from its file when needed
+		pos.text = content
+		positions.values = append(positions.values, pos)
+		if isGeneric {
+			generic.setBracedContent(content)
+			return block, nil
+		}
+		if err := block.SetContent(content); err != nil {
+			return nil, err
+		}
+		block.(*TextBlock).spill = spill
+		return block, nil
 	}
 
 	return nil, nil
@@ -1,7 +1,9 @@
 package bru
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -96,3 +98,238 @@ meta {
 		fmt.Println("------------")
 	}
 }
+
+func TestDecodingTextSpill(t *testing.T) {
+	simpleFile := `body {
+  {
+    "hello": "world"
+  }
+}`
+	read, err := Read([]byte(simpleFile), WithTextSpill(10, t.TempDir()))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	block := read[0].(*TextBlock)
+	if !block.Spilled() || block.Content != "" {
+		t.Fatal("text block should have been spilled")
+	}
+	defer block.Release()
+	encoded, err := Write(read)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(encoded) != simpleFile {
+		t.Fatalf("unexpected encoding of spilled block:\n%s", string(encoded))
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	read, err := ReadFile("testFiles/User/User Info.bru")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(read) != 3 || read[0].GetName() != "meta" {
+		t.Fatalf("unexpected blocks: %v", read)
+	}
+}
+
+func TestReadPartial(t *testing.T) {
+	simpleFile := `meta {
+  name: toto
+}
+
+get {
+  url
+}
+
+vars:secret [
+  a,
+  b
+]`
+	read, errs := ReadPartial([]byte(simpleFile))
+	if len(errs) != 1 || errs[0].(*SyntaxError).Line != 6 {
+		t.Fatalf("expected a single error at line 6, got %v", errs)
+	}
+	if len(read) != 2 || read[0].GetName() != "meta" || read[1].GetName() != "vars" {
+		t.Fatalf("unexpected blocks: %v", read)
+	}
+}
+
+func TestReadPartialComments(t *testing.T) {
+	simpleFile := `get {
+  url
+}
+
+# Who is calling
+// and why
+meta {
+  # the name
+  name: toto
+}`
+	read, errs := ReadPartial([]byte(simpleFile))
+	if len(errs) != 1 || len(read) != 1 {
+		t.Fatalf("unexpected result %v, %v", read, errs)
+	}
+	expected := []Comment{
+		{"# Who is calling", 15, 5, CommentBeforeBlock},
+		{"// and why", 32, 6, CommentBeforeBlock},
+		{"# the name", 52, 8, 0},
+	}
+	if comments := blockComments(read[0]); !reflect.DeepEqual(comments, expected) {
+		t.Fatalf("unexpected comments %v", comments)
+	}
+}
+
+func TestShiftSyntaxErrors(t *testing.T) {
+	first := &SyntaxError{msg: "first", Offset: 3, Line: 1, Column: 4}
+	second := &SyntaxError{msg: "second", Offset: 10, Line: 2, Column: 2}
+	shiftSyntaxErrors(errors.Join(first, second), 20, 3, 5)
+	if *first != (SyntaxError{"first", 23, 4, 9}) || *second != (SyntaxError{"second", 30, 5, 2}) {
+		t.Fatalf("unexpected positions %+v, %+v", first, second)
+	}
+}
+
+func TestDecodingDisabledEntries(t *testing.T) {
+	simpleFile := `headers {
+  ~X-Debug: true
+  Accept: application/json
+}
+
+vars:secret [
+  access_key,
+  ~transactionId
+]`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	headers := read[0].(*DictionaryBlock).Content
+	if headers[0] != (DictionaryElement{"X-Debug", "true", true}) || headers[1] != (DictionaryElement{"Accept", "application/json", false}) {
+		t.Fatalf("unexpected headers: %v", headers)
+	}
+	vars := read[1].(*ArrayBlock).Content
+	if vars[0] != (ArrayElement{"access_key", false}) || vars[1] != (ArrayElement{"transactionId", true}) {
+		t.Fatalf("unexpected vars: %v", vars)
+	}
+	decodeAndEncodeFileWithDefault([]byte(simpleFile), t)
+}
+
+func TestDecodingMultilineValue(t *testing.T) {
+	simpleFile := `body:form-urlencoded {
+  query: '''
+    {
+      user(id: 1) {
+        name
+      }
+    }
+  '''
+  title: it''s fine
+  empty: ''
+}`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	content := read[0].(*DictionaryBlock).Content
+	if content[0].Value != "{\n  user(id: 1) {\n    name\n  }\n}" {
+		t.Fatalf("unexpected multiline value %q", content[0].Value)
+	}
+	if content[1].Value != "it''s fine" || content[2].Value != "''" {
+		t.Fatalf("unexpected values %v", content)
+	}
+	decodeAndEncodeFileWithDefault([]byte(simpleFile), t)
+}
+
+func TestDecodingUnbalancedBraces(t *testing.T) {
+	simpleFile := `script:pre-request {
+  // {
+  const a = 1;
+}
+
+docs {
+  Use the { placeholder, or "}" to close it
+  }
+}
+
+body:json {
+  {
+    "nested": {
+  }
+}
+
+get {
+  url: https://toto.com
+}`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(read) != 4 {
+		t.Fatalf("expected 4 blocks, got %d", len(read))
+	}
+	expected := []string{
+		"  // {\n  const a = 1;",
+		"  Use the { placeholder, or \"}\" to close it\n  }",
+		"  {\n    \"nested\": {\n  }",
+	}
+	for i, content := range expected {
+		if text := read[i].(*TextBlock).Content; text != content {
+			t.Fatalf("unexpected content %q", text)
+		}
+	}
+	decodeAndEncodeFileWithDefault([]byte(simpleFile), t)
+
+	// Unindented content is not read, like in Bruno
+	if _, err := Read([]byte("body:json {\n{\n  \"a\": 1\n}\n}")); err == nil {
+		t.Fatal("a closing brace in the first column should end the block")
+	}
+}
+
+func TestDecodingCommaValues(t *testing.T) {
+	simpleFile := `headers {
+  Accept: application/json, text/plain
+  Cache-Control: no-cache, no-store,
+  ~X-List: a,b,c
+}`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []DictionaryElement{
+		{"Accept", "application/json, text/plain", false},
+		{"Cache-Control", "no-cache, no-store,", false},
+		{"X-List", "a,b,c", true},
+	}
+	if content := read[0].(*DictionaryBlock).Content; !reflect.DeepEqual(content, expected) {
+		t.Fatalf("unexpected content %v", content)
+	}
+	decodeAndEncodeFileWithDefault([]byte(simpleFile), t)
+}
+
+func TestDecodingColonKeys(t *testing.T) {
+	simpleFile := `headers {
+  Authorization: Basic xx:yy
+  "http://toto.com:8080": proxy
+  ~"a:b": c:d
+  "say: \"hi\"": ok
+  "#tag": 1
+}`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []DictionaryElement{
+		{"Authorization", "Basic xx:yy", false},
+		{"http://toto.com:8080", "proxy", false},
+		{"a:b", "c:d", true},
+		{`say: "hi"`, "ok", false},
+		{"#tag", "1", false},
+	}
+	if content := read[0].(*DictionaryBlock).Content; !reflect.DeepEqual(content, expected) {
+		t.Fatalf("unexpected content %v", content)
+	}
+	decodeAndEncodeFileWithDefault([]byte(simpleFile), t)
+	if _, err := Read([]byte("headers {\n  \"a:b\" x: c\n}")); err == nil {
+		t.Fatal("should have failed")
+	}
+}
@@ -0,0 +1,152 @@
+package bru
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// A Variation is a copy of a request made by Collection.DuplicateRequest
+type Variation struct {
+	Name string // name of the copy
+	// Overrides are the values changed in the copy, by key:
+	//   - url sets the URL of the method block
+	//   - body sets the content of the text body block of the body mode, such as body:json
+	//   - headers.<name> sets the header <name>, adding it if the copy has none
+	Overrides map[string]string
+}
+
+// DuplicateRequest adds to the collection a copy of the request whose file is at requestPath, with the changes of v.
+// The copy is put in the same folder right after the request, the sequence numbers of the folder
+// being rewritten as by Renumber, and gets a file named after v.Name with SafeFilename.
+// Nothing is written, use Save to create the file.
+func (c *Collection) DuplicateRequest(requestPath string, v Variation) (*Request, error) {
+	r, ok := c.Request(requestPath)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", requestPath, errRequestNotFound)
+	}
+	blocks := make([]ContentBlock, len(r.Blocks))
+	for i, b := range r.Blocks {
+		clone, err := cloneBlock(b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", requestPath, err)
+		}
+		blocks[i] = clone
+	}
+	dup := &Request{Name: v.Name, Blocks: blocks}
+	if err := dup.override(v.Overrides); err != nil {
+		return nil, fmt.Errorf("%s: %w", requestPath, err)
+	}
+	meta, ok := FindBlock(dup.Blocks, "meta").(*DictionaryBlock)
+	if !ok {
+		meta = &DictionaryBlock{Name: "meta"}
+		dup.Blocks = append([]ContentBlock{meta}, dup.Blocks...)
+	}
+	setEntry(meta, "name", v.Name, false)
+
+	folders, _ := findRequest(c.Folders, c.Requests, r)
+	requests := &c.Requests
+	if len(folders) > 0 {
+		requests = &folders[len(folders)-1].Requests
+	}
+	taken := map[string]bool{}
+	var added []*Request
+	for _, sibling := range *requests {
+		taken[strings.ToLower(strings.TrimSuffix(path.Base(sibling.Path), ".bru"))] = true
+		added = append(added, sibling)
+		if sibling == r {
+			added = append(added, dup)
+		}
+	}
+	dup.Path = path.Join(path.Dir(r.Path), UniqueFilename(v.Name, taken)+".bru")
+	// Numbered as the request, the copy comes right after it
+	if seq, ok := r.Seq(); ok {
+		dup.setSeq(seq)
+	}
+	renumberRequests(added)
+	*requests = added
+	return dup, nil
+}
+
+// override sets the values of a Variation in the blocks of the request
+func (r *Request) override(overrides map[string]string) error {
+	for key, value := range overrides {
+		switch {
+		case key == "url":
+			method, ok := FindBlock(r.Blocks, strings.ToLower(r.Method())).(*DictionaryBlock)
+			if !ok {
+				return errors.New("cannot override url: no method block")
+			}
+			setEntry(method, "url", value, false)
+		case key == "body":
+			tag := "body:" + r.BodyMode()
+			body, ok := FindBlock(r.Blocks, tag).(*TextBlock)
+			if !ok {
+				return fmt.Errorf("cannot override body: no %s text block", tag)
+			}
+			if err := body.SetContent(value); err != nil {
+				return err
+			}
+		case strings.HasPrefix(key, "headers."):
+			headers, ok := FindBlock(r.Blocks, "headers").(*DictionaryBlock)
+			if !ok {
+				headers = &DictionaryBlock{Name: "headers"}
+				r.Blocks = append(r.Blocks, headers)
+			}
+			setEntry(headers, strings.TrimPrefix(key, "headers."), value, true)
+		default:
+			return fmt.Errorf("unknown override %q", key)
+		}
+	}
+	return nil
+}
+
+// setEntry sets the value of the enabled entries with the given key, adding one if there is none.
+// Keys are compared case-insensitively with foldCase, as header names.
+func setEntry(b *DictionaryBlock, key, value string, foldCase bool) {
+	set := false
+	for i, e := range b.Content {
+		if !e.Disabled && (e.Key == key || foldCase && strings.EqualFold(e.Key, key)) {
+			b.Content[i].Value, set = value, true
+		}
+	}
+	if !set {
+		b.Content = append(b.Content, DictionaryElement{key, value, false})
+	}
+}
+
+// cloneBlock returns a copy of b which can be modified without changing it.
+// The content of a spilled text block is read back in memory, so that both blocks can be released.
+func cloneBlock(b ContentBlock) (ContentBlock, error) {
+	switch t := b.(type) {
+	case *DictionaryBlock:
+		return cloneDictionary(t), nil
+	case *ArrayBlock:
+		clone := *t
+		clone.Content = append([]ArrayElement(nil), t.Content...)
+		return &clone, nil
+	case *TextBlock:
+		clone := *t
+		if t.Spilled() {
+			r, err := t.Reader()
+			if err != nil {
+				return nil, err
+			}
+			defer r.Close()
+			content, err := io.ReadAll(r)
+			if err != nil {
+				return nil, err
+			}
+			clone.Content, clone.spill = string(content), ""
+		}
+		return &clone, nil
+	case *GenericBlock:
+		clone := *t
+		clone.Dictionary = append([]DictionaryElement(nil), t.Dictionary...)
+		clone.Array = append([]ArrayElement(nil), t.Array...)
+		return &clone, nil
+	}
+	return b, nil
+}
@@ -0,0 +1,63 @@
+package bru
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDuplicateRequest(t *testing.T) {
+	c, err := LoadCollectionFS(fstest.MapFS{
+		"Users/Create.bru": {Data: []byte("meta {\n  name: Create\n  seq: 1\n}\n\npost {\n  url: {{baseUrl}}/users\n  body: json\n}\n\n" +
+			"headers {\n  content-type: application/json\n}\n\nbody:json {\n  {\"name\": \"toto\"}\n}")},
+		"Users/Delete.bru": {Data: []byte("meta {\n  name: Delete\n  seq: 2\n}\n\ndelete {\n  url: {{baseUrl}}/users/1\n}")},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	dup, err := c.DuplicateRequest("Users/Create.bru", Variation{"Create: empty", map[string]string{
+		"url":                  "{{baseUrl}}/users?dry=1",
+		"body":                 "  {}",
+		"headers.Content-Type": "text/plain",
+		"headers.X-Case":       "empty",
+	}})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if dup.Path != "Users/Create- empty.bru" || dup.Name != "Create: empty" || dup.URL() != "{{baseUrl}}/users?dry=1" {
+		t.Fatalf("unexpected copy %+v", dup)
+	}
+	expected := []DictionaryElement{{"content-type", "text/plain", false}, {"X-Case", "empty", false}}
+	if headers := dup.Headers(); !reflect.DeepEqual(headers, expected) {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+	if body := FindBlock(dup.Blocks, "body:json").(*TextBlock).Content; body != "  {}" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	original := c.Folders[0].Requests[0]
+	if original.URL() != "{{baseUrl}}/users" || len(original.Headers()) != 1 || FindBlock(original.Blocks, "body:json").(*TextBlock).Content == "  {}" {
+		t.Fatalf("the original request should be unchanged: %+v", original)
+	}
+	var names []string
+	for _, r := range c.Folders[0].Requests {
+		seq, _ := r.Seq()
+		names = append(names, r.Name+":"+strconv.Itoa(seq))
+	}
+	if !reflect.DeepEqual(names, []string{"Create:1", "Create: empty:2", "Delete:3"}) {
+		t.Fatalf("unexpected requests %v", names)
+	}
+
+	if again, err := c.DuplicateRequest("Users/Create.bru", Variation{Name: "Create: empty"}); err != nil || again.Path != "Users/Create- empty_1.bru" {
+		t.Fatalf("unexpected second copy %v, %v", again, err)
+	}
+	if _, err := c.DuplicateRequest("Users/Delete.bru", Variation{"Delete", map[string]string{"body": "{}"}}); err == nil {
+		t.Fatal("a request without body should not get one")
+	}
+	if _, err := c.DuplicateRequest("Users/Delete.bru", Variation{"Delete", map[string]string{"method": "get"}}); err == nil {
+		t.Fatal("unknown overrides should be rejected")
+	}
+	if _, err := c.DuplicateRequest("Missing.bru", Variation{Name: "Missing"}); err == nil {
+		t.Fatal("should have failed")
+	}
+}
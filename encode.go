@@ -1,46 +1,98 @@
 package bru // Copyright 2010 The Go Authors. All rights reserved.
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"strings"
 )
 
 type Encoder struct {
-	indent             int
-	lineSep            string
-	addTrailingLineEnd bool
+	opts Options
 }
 
-// Using default encoder for write
-// To customize parameter create an encoder
-func Write(data []ContentBlock) ([]byte, error) {
-	return (&Encoder{}).Write(data)
+// NewEncoder creates an encoder customized by the given options
+func NewEncoder(opts ...Option) *Encoder {
+	return &Encoder{opts: newOptions(opts)}
+}
+
+// Write encodes the blocks using an encoder built from the given options
+func Write(data []ContentBlock, opts ...Option) ([]byte, error) {
+	return NewEncoder(opts...).Write(data)
 }
 
 func (b *Encoder) Write(data []ContentBlock) ([]byte, error) {
-	var e encodeState
-	err := e.marshal(data, b)
-	if err != nil {
+	var buf bytes.Buffer
+	if _, err := b.WriteTo(&buf, data); err != nil {
 		return nil, err
 	}
-	// Remove one trailing \n
-	toWrite := e.Bytes()
-	n := len(toWrite)
-	if n > 2 && toWrite[n-1] == '\n' && toWrite[n-2] == '\n' {
-		toWrite = toWrite[:n-2+b.GetEndOffset()]
-	}
-	buf := append([]byte(nil), toWrite...)
+	return buf.Bytes(), nil
+}
 
-	return buf, nil
+// WriteTo encodes the blocks directly to w, returning the number of bytes written.
+func (b *Encoder) WriteTo(w io.Writer, data []ContentBlock) (int64, error) {
+	e := encodeState{w: bufio.NewWriter(w), crlf: b.opts.CRLF}
+	if err := e.marshal(data, b); err != nil {
+		return e.n, err
+	}
+	if len(data) > 0 && b.GetEndOffset() > 0 {
+		e.WriteString("\n")
+	}
+	if e.err == nil {
+		e.err = e.w.Flush()
+	}
+	return e.n, e.err
 }
 
-// An encodeState encodes JSON into a bytes.Buffer.
+// An encodeState encodes bru into a buffered writer.
+// Write errors are kept and make all following writes no-ops, to be checked once at the end.
 type encodeState struct {
-	bytes.Buffer // accumulated output
+	w    *bufio.Writer
+	n    int64 // bytes written so far
+	err  error // first write error
+	crlf bool  // line endings are turned into CRLF
+}
+
+func (e *encodeState) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	if e.crlf {
+		e.WriteString(string(p))
+		if e.err != nil {
+			return 0, e.err
+		}
+		return len(p), nil
+	}
+	n, err := e.w.Write(p)
+	e.n += int64(n)
+	e.err = err
+	return n, err
+}
+
+func (e *encodeState) WriteString(s string) {
+	if e.err != nil {
+		return
+	}
+	if e.crlf {
+		s = toCRLF(s)
+	}
+	n, err := e.w.WriteString(s)
+	e.n += int64(n)
+	e.err = err
 }
 
 func (e *encodeState) marshal(data []ContentBlock, b *Encoder) (err error) {
-	for _, d := range data {
+	for i, d := range data {
+		if i > 0 {
+			// Blocks are separated by an empty line
+			e.WriteString("\n\n")
+		}
+		if g, ok := d.(*GenericBlock); ok {
+			// Written as a block of its inferred kind
+			d = g.Block()
+		}
+		e.writeComments(blockComments(d), CommentBeforeBlock, 0)
 		// Add the first line
 		e.WriteString(d.GetName())
 		if d.GetType() != "" {
@@ -51,49 +103,103 @@ func (e *encodeState) marshal(data []ContentBlock, b *Encoder) (err error) {
 		case *DictionaryBlock:
 			e.WriteString(" {\n")
 			for i, v := range c.Content {
+				e.writeComments(c.Comments, i, b.GetIndent())
+				value := v.Value
+				if strings.Contains(value, "\n") {
+					if value, err = quoteMultiline(value, b.GetIndent()); err != nil {
+						return fmt.Errorf("%s %s: %w", tagOf(d), v.Key, err)
+					}
+				}
 				if i == len(c.Content)-1 {
 					// Last
-					e.WriteString(fmt.Sprintf("%s%s: %s\n", strings.Repeat(" ", b.GetIndent()), v.Key, v.Value))
+					e.WriteString(fmt.Sprintf("%s%s%s: %s\n", strings.Repeat(" ", b.GetIndent()), disabledPrefix(v.Disabled), quoteKey(v.Key), value))
 				} else {
-					i++
-					e.WriteString(fmt.Sprintf("%s%s: %s%s\n", strings.Repeat(" ", b.GetIndent()), v.Key, v.Value, b.GetLineSep()))
+					e.WriteString(fmt.Sprintf("%s%s%s: %s%s\n", strings.Repeat(" ", b.GetIndent()), disabledPrefix(v.Disabled), quoteKey(v.Key), value, b.GetLineSep()))
 				}
 			}
-			e.WriteString("}\n\n")
+			e.writeComments(c.Comments, len(c.Content), b.GetIndent())
+			e.WriteString("}")
 		case *TextBlock:
 			e.WriteString(" {\n")
-			e.WriteString(c.Content + "\n")
-			e.WriteString("}\n\n")
+			if err := e.writeText(c); err != nil {
+				return err
+			}
+			e.WriteString("\n}")
 		case *ArrayBlock:
 			e.WriteString(" [\n")
 			for i, v := range c.Content {
+				e.writeComments(c.Comments, i, b.GetIndent())
 				if i == len(c.Content)-1 {
 					// Last
-					e.WriteString(fmt.Sprintf("%s%s\n", strings.Repeat(" ", b.GetIndent()), v))
+					e.WriteString(fmt.Sprintf("%s%s%s\n", strings.Repeat(" ", b.GetIndent()), disabledPrefix(v.Disabled), v.Value))
 				} else {
-					e.WriteString(fmt.Sprintf("%s%s%s\n", strings.Repeat(" ", b.GetIndent()), v, b.GetLineSep()))
+					// Array values are always comma separated
+					e.WriteString(fmt.Sprintf("%s%s%s,\n", strings.Repeat(" ", b.GetIndent()), disabledPrefix(v.Disabled), v.Value))
 				}
 			}
-			e.WriteString("]\n\n")
+			e.writeComments(c.Comments, len(c.Content), b.GetIndent())
+			e.WriteString("]")
 		}
+		for _, comment := range blockComments(d) {
+			if comment.Entry == CommentAfterBlock {
+				e.WriteString("\n" + comment.Text)
+			}
+		}
+	}
+	return e.err
+}
+
+// writeComments writes the comments placed at entry, each on its own line
+func (e *encodeState) writeComments(comments []Comment, entry int, indent int) {
+	for _, c := range comments {
+		if c.Entry == entry {
+			e.WriteString(strings.Repeat(" ", indent) + c.Text + "\n")
+		}
+	}
+}
+
+// disabledPrefix returns the prefix marking an entry as disabled
+func disabledPrefix(disabled bool) string {
+	if disabled {
+		return "~"
 	}
-	return nil
+	return ""
 }
 
 func (b *Encoder) GetIndent() int {
-	if b.indent != 0 {
-		return b.indent
+	if b.opts.Indent > 0 {
+		return b.opts.Indent
 	}
 	return 2
 }
 
 func (b *Encoder) GetEndOffset() int {
-	if !b.addTrailingLineEnd {
+	if !b.opts.TrailingNewline {
 		return 0
 	}
 	return 1
 }
 
 func (b *Encoder) GetLineSep() string {
-	return b.lineSep
+	return b.opts.LineSep
+}
+
+// Options returns the options used by the encoder
+func (b *Encoder) Options() Options {
+	return b.opts
+}
+
+// writeText writes the content of a text block, reading it back from disk if it was spilled
+func (e *encodeState) writeText(t *TextBlock) error {
+	if !t.Spilled() {
+		e.WriteString(t.Content)
+		return nil
+	}
+	r, err := t.Reader()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(e, r)
+	return err
 }
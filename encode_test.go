@@ -1,6 +1,7 @@
 package bru
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
@@ -31,8 +32,7 @@ func decodeAndEncodeFileWithNewLine(file []byte, t *testing.T) {
 	if err != nil {
 		t.Fatal(err.Error())
 	}
-	encoder := Encoder{addTrailingLineEnd: true}
-	encoded, err := encoder.Write(read)
+	encoded, err := Write(read, WithTrailingNewline(true))
 	if err != nil {
 		t.Fatal(err.Error())
 	}
@@ -58,3 +58,115 @@ func decodeAndEncodeFileWithDefault(file []byte, t *testing.T) {
 		t.Fatal("encoded content is different from original file")
 	}
 }
+
+func TestEncodingWithOptions(t *testing.T) {
+	blocks := []ContentBlock{&DictionaryBlock{
+		Name:    "meta",
+		Content: []DictionaryElement{{"name", "toto", false}, {"seq", "1", false}},
+	}}
+	encoded, err := Write(blocks, WithIndent(4), WithLineSeparator(","), WithTrailingNewline(true))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := "meta {\n    name: toto,\n    seq: 1\n}\n"
+	if string(encoded) != expected {
+		t.Fatalf("unexpected encoding:\n%s", string(encoded))
+	}
+}
+
+func TestEncodingWriteTo(t *testing.T) {
+	blocks := []ContentBlock{
+		&DictionaryBlock{Name: "get", Content: []DictionaryElement{{"url", "https://toto.com", false}}},
+		&TextBlock{Name: "body", Type: "json", Content: "{}"},
+	}
+	var buf bytes.Buffer
+	n, err := NewEncoder().WriteTo(&buf, blocks)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := "get {\n  url: https://toto.com\n}\n\nbody:json {\n{}\n}"
+	if buf.String() != expected || n != int64(len(expected)) {
+		t.Fatalf("unexpected encoding (%d bytes):\n%s", n, buf.String())
+	}
+}
+
+func TestEncodingArray(t *testing.T) {
+	decodeAndEncodeFileWithDefault([]byte(`vars:secret [
+  access_key,
+  access_secret,
+  ~transactionId
+]`), t)
+}
+
+func TestEncodingPatch(t *testing.T) {
+	decodeAndEncodeFileWithDefault([]byte(`patch {
+  url: {{baseUrl}}/users/1
+  body: json
+  auth: none
+}`), t)
+}
+
+func TestEncodingDocs(t *testing.T) {
+	decodeAndEncodeFileWithDefault([]byte(`docs {
+  # User Info
+  
+  Returns the **public** profile of a user.
+
+  - name
+  - login
+}`), t)
+}
+
+func TestEncodingSparql(t *testing.T) {
+	decodeAndEncodeFileWithDefault([]byte(`post {
+  url: https://query.wikidata.org/sparql
+  body: sparql
+  auth: none
+}
+
+body:sparql {
+  SELECT ?item WHERE {
+    ?item wdt:P31 wd:Q146.
+  }
+  LIMIT 10
+}`), t)
+}
+
+func TestEncodingKeyedElements(t *testing.T) {
+	blocks := []ContentBlock{
+		&DictionaryBlock{Name: "headers", Content: []DictionaryElement{{Key: "Accept", Value: "*/*"}, {Key: "X-Debug", Value: "true", Disabled: true}}},
+		&ArrayBlock{Name: "vars", Type: "secret", Content: []ArrayElement{{Value: "token"}}},
+	}
+	encoded, err := Write(blocks)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := "headers {\n  Accept: */*\n  ~X-Debug: true\n}\n\nvars:secret [\n  token\n]"
+	if string(encoded) != expected {
+		t.Fatalf("unexpected encoding:\n%s", string(encoded))
+	}
+}
+
+func TestEncodingMultilineQuotes(t *testing.T) {
+	values := []string{"it's '''quoted'''\nhere", "'''a\nb'''", "a\n  ''' b\nc"}
+	for _, value := range values {
+		blocks := []ContentBlock{&DictionaryBlock{Name: "vars", Content: []DictionaryElement{{Key: "v", Value: value}}}}
+		encoded, err := Write(blocks)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		read, err := Read(encoded)
+		if err != nil {
+			t.Fatalf("cannot read %q back: %s", encoded, err)
+		}
+		if got := read[0].(*DictionaryBlock).Content[0].Value; got != value {
+			t.Fatalf("unexpected value %q read back, expected %q", got, value)
+		}
+	}
+	for _, value := range []string{"a\n'''\nb", "a\n  '''  \nb"} {
+		blocks := []ContentBlock{&DictionaryBlock{Name: "vars", Content: []DictionaryElement{{Key: "v", Value: value}}}}
+		if _, err := Write(blocks); err == nil || err.Error() != "vars v: multiline value cannot have a line of '''" {
+			t.Fatalf("unexpected error %v for %q", err, value)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package bru
+
+import (
+	"sort"
+	"strings"
+)
+
+// enforceHeadersSetting is the setting opting requests out of EnforceHeaders when set to false
+const enforceHeadersSetting = "enforceHeaders"
+
+// EnforceHeaders sets the headers of policy, by name, on every request of the collection which is not
+// sent with them already, adding them to its headers block or updating them there.
+// Requests are opted out with an enforceHeaders: false entry in their settings block,
+// or in the settings block of the collection or of a folder containing them, see EffectiveSettings.
+// The paths of the changed requests are returned. Nothing is written, use Save to update the files.
+func EnforceHeaders(c *Collection, policy map[string]string) ([]string, error) {
+	names := make([]string, 0, len(policy))
+	for name := range policy {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var changed []string
+	for _, r := range c.Find(func(*Request) bool { return true }) {
+		settings, _, err := c.EffectiveSettings(r)
+		if err != nil {
+			return changed, err
+		}
+		if optedOutOfHeaders(settings) {
+			continue
+		}
+		effective, err := c.EffectiveHeaders(r.Path)
+		if err != nil {
+			return changed, err
+		}
+		updated := false
+		for _, name := range names {
+			if sentWith(effective, name, policy[name]) {
+				continue
+			}
+			headers, ok := FindBlock(r.Blocks, "headers").(*DictionaryBlock)
+			if !ok {
+				headers = &DictionaryBlock{Name: "headers"}
+				r.Blocks = append(r.Blocks, headers)
+			}
+			setEntry(headers, name, policy[name], true)
+			updated = true
+		}
+		if updated {
+			changed = append(changed, r.Path)
+		}
+	}
+	return changed, nil
+}
+
+// optedOutOfHeaders reports whether the settings opt out of EnforceHeaders
+func optedOutOfHeaders(s Settings) bool {
+	for _, e := range s.Other {
+		if !e.Disabled && e.Key == enforceHeadersSetting && e.Value == "false" {
+			return true
+		}
+	}
+	return false
+}
+
+// sentWith reports whether the header name is among headers with the given value
+func sentWith(headers []EffectiveHeader, name, value string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value == value
+		}
+	}
+	return false
+}
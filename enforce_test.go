@@ -0,0 +1,44 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEnforceHeaders(t *testing.T) {
+	c, err := LoadCollectionFS(fstest.MapFS{
+		"collection.bru":       {Data: []byte("headers {\n  X-Client: qa-suite\n}")},
+		"Users/Get User.bru":   {Data: []byte("get {\n  url: {{baseUrl}}/users/1\n}\n\nheaders {\n  x-team: old\n}")},
+		"Users/List.bru":       {Data: []byte("get {\n  url: {{baseUrl}}/users\n}\n\nheaders {\n  X-Team: users\n}")},
+		"Legacy/folder.bru":    {Data: []byte("settings {\n  enforceHeaders: false\n}")},
+		"Legacy/Old.bru":       {Data: []byte("get {\n  url: {{baseUrl}}/old\n}")},
+		"Health.bru":           {Data: []byte("get {\n  url: {{baseUrl}}/health\n}\n\nsettings {\n  enforceHeaders: true\n}")},
+		"Users/Other Team.bru": {Data: []byte("get {\n  url: {{baseUrl}}/other\n}\n\nheaders {\n  X-Client: other\n}")},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	changed, err := EnforceHeaders(c, map[string]string{"X-Client": "qa-suite", "X-Team": "users"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(changed, []string{"Health.bru", "Users/Get User.bru", "Users/Other Team.bru"}) {
+		t.Fatalf("unexpected changed requests %v", changed)
+	}
+	health, _ := c.Request("Health.bru")
+	if headers := health.Headers(); !reflect.DeepEqual(headers, []DictionaryElement{{"X-Team", "users", false}}) {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+	get, _ := c.Request("Users/Get User.bru")
+	if headers := get.Headers(); !reflect.DeepEqual(headers, []DictionaryElement{{"x-team", "users", false}}) {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+	other, _ := c.Request("Users/Other Team.bru")
+	if headers := other.Headers(); !reflect.DeepEqual(headers, []DictionaryElement{{"X-Client", "qa-suite", false}, {"X-Team", "users", false}}) {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+	if old, _ := c.Request("Legacy/Old.bru"); len(old.Blocks) != 1 {
+		t.Fatalf("opted out request should be unchanged %+v", old)
+	}
+}
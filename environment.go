@@ -0,0 +1,132 @@
+package bru
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// An Environment is a set of variables of a collection, stored in a file of its environments directory
+type Environment struct {
+	Name      string // file name without extension
+	Variables []EnvironmentVariable
+	// positions locate the variables in the file the environment was read from, by block tag
+	positions map[string]blockPositions
+}
+
+// An EnvironmentVariable is a variable of an environment.
+// The values of secret variables are not stored in the file, only their name is.
+type EnvironmentVariable struct {
+	Name     string
+	Value    string
+	Disabled bool
+	Secret   bool
+}
+
+// ParseEnvironment reads an environment file, made of a vars block and a vars:secret block.
+// The name of the returned environment is left empty.
+func ParseEnvironment(data []byte, opts ...Option) (*Environment, error) {
+	blocks, err := Read(data, opts...)
+	if err != nil {
+		return nil, err
+	}
+	env := &Environment{positions: map[string]blockPositions{}}
+	for _, b := range blocks {
+		switch b := b.(type) {
+		case *DictionaryBlock:
+			env.positions[tagOf(b)] = b.positions
+		case *ArrayBlock:
+			env.positions[tagOf(b)] = b.positions
+		}
+		switch tagOf(b) {
+		case "vars":
+			for _, e := range b.(*DictionaryBlock).Content {
+				env.Variables = append(env.Variables, EnvironmentVariable{e.Key, e.Value, e.Disabled, false})
+			}
+		case "vars:secret":
+			for _, e := range b.(*ArrayBlock).Content {
+				env.Variables = append(env.Variables, EnvironmentVariable{e.Value, "", e.Disabled, true})
+			}
+		default:
+			return nil, fmt.Errorf("unexpected block %s in environment", tagOf(b))
+		}
+	}
+	return env, nil
+}
+
+// EncodeEnvironment returns the content of the file of an environment.
+// The values of secret variables are not written.
+func EncodeEnvironment(env *Environment, opts ...Option) ([]byte, error) {
+	return Write(env.blocks(), opts...)
+}
+
+// blocks returns the blocks of the file of the environment
+func (e *Environment) blocks() []ContentBlock {
+	vars := &DictionaryBlock{Name: "vars", positions: e.positions["vars"]}
+	secrets := &ArrayBlock{Name: "vars", Type: "secret", positions: e.positions["vars:secret"]}
+	for _, v := range e.Variables {
+		if v.Secret {
+			secrets.Content = append(secrets.Content, ArrayElement{v.Name, v.Disabled})
+		} else {
+			vars.Content = append(vars.Content, DictionaryElement{v.Name, v.Value, v.Disabled})
+		}
+	}
+	var blocks []ContentBlock
+	if len(vars.Content) > 0 {
+		blocks = append(blocks, vars)
+	}
+	if len(secrets.Content) > 0 {
+		blocks = append(blocks, secrets)
+	}
+	return blocks
+}
+
+// Get returns the value of the enabled variable with the given name
+func (e *Environment) Get(name string) (string, bool) {
+	for _, v := range e.Variables {
+		if !v.Disabled && v.Name == name {
+			return v.Value, true
+		}
+	}
+	return "", false
+}
+
+// loadEnvironments reads the environment files of the environments directory of fsys, a missing directory having none.
+// The errors are given to fail, loading stopping if it returns an error.
+func loadEnvironments(fsys fs.FS, opts []Option, fail func(name string, err error) error) ([]*Environment, error) {
+	if isSymlink(fsys, environmentsDir) {
+		return nil, fail(environmentsDir, ErrSymlink)
+	}
+	entries, err := fs.ReadDir(fsys, environmentsDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fail(environmentsDir, err)
+	}
+	var envs []*Environment
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".bru" {
+			continue
+		}
+		name := path.Join(environmentsDir, e.Name())
+		data, err := []byte(nil), ErrSymlink
+		if e.Type()&fs.ModeSymlink == 0 {
+			data, err = fs.ReadFile(fsys, name)
+		}
+		if err == nil {
+			var env *Environment
+			if env, err = ParseEnvironment(data, opts...); err == nil {
+				env.Name = strings.TrimSuffix(e.Name(), ".bru")
+				envs = append(envs, env)
+				continue
+			}
+		}
+		if err := fail(name, err); err != nil {
+			return nil, err
+		}
+	}
+	return envs, nil
+}
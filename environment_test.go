@@ -0,0 +1,67 @@
+package bru
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestEnvironment(t *testing.T) {
+	data := []byte(`vars {
+  baseUrl: https://api.github.com
+  ~user: toto
+}
+
+vars:secret [
+  token,
+  ~password
+]`)
+	env, err := ParseEnvironment(data)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []EnvironmentVariable{
+		{"baseUrl", "https://api.github.com", false, false},
+		{"user", "toto", true, false},
+		{"token", "", false, true},
+		{"password", "", true, true},
+	}
+	if !reflect.DeepEqual(env.Variables, expected) {
+		t.Fatalf("unexpected variables %v", env.Variables)
+	}
+	if v, ok := env.Get("user"); ok || v != "" {
+		t.Fatal("disabled variables should not be found")
+	}
+	env.Variables[2].Value = "not written"
+	encoded, err := EncodeEnvironment(env)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(encoded) != string(data) {
+		t.Fatalf("unexpected encoding:\n%s", encoded)
+	}
+	if _, err := ParseEnvironment([]byte("meta {\n}")); err == nil {
+		t.Fatal("should have failed")
+	}
+}
+
+func TestCollectionEnvironments(t *testing.T) {
+	c, err := LoadCollection("testFiles")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(c.Environments) != 1 || c.Environments[0].Name != "Github" {
+		t.Fatalf("unexpected environments %v", c.Environments)
+	}
+	original, err := os.ReadFile("testFiles/environments/Github.bru")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	encoded, err := EncodeEnvironment(c.Environments[0], WithTrailingNewline(true))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(encoded) != string(original) {
+		t.Fatalf("unexpected encoding:\n%s", encoded)
+	}
+}
@@ -0,0 +1,24 @@
+package bru
+
+import "os"
+
+// ReadFile decodes the bru file at path.
+// The file is memory-mapped when the platform supports it, and read in memory otherwise.
+// The decoded blocks never reference the mapped memory, so they stay valid after ReadFile returns.
+func ReadFile(path string, opts ...Option) ([]ContentBlock, error) {
+	data, release, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return Read(data, opts...)
+}
+
+// readFile is the fallback of mapFile, reading the whole file in memory
+func readFile(path string) ([]byte, func(), error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() {}, nil
+}
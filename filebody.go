@@ -0,0 +1,46 @@
+package bru
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// FileBodyEntry is a file of a body:file block.
+// Only the selected entry is sent by Bruno, the others are kept disabled with a '~' prefix.
+type FileBodyEntry struct {
+	Path        string
+	ContentType string
+	Selected    bool
+}
+
+// fileBodyValue matches the value of a body:file entry: @file(<path>) @contentType(<type>)
+var fileBodyValue = regexp.MustCompile(`^@file\((.*?)\)\s*(?:@contentType\((.*)\))?$`)
+
+// FileBodyEntries reads the entries of a body:file block
+func (t *DictionaryBlock) FileBodyEntries() ([]FileBodyEntry, error) {
+	if err := checkBlockTag(t, "body", "file"); err != nil {
+		return nil, err
+	}
+	entries := make([]FileBodyEntry, 0, len(t.Content))
+	for _, e := range t.Content {
+		match := fileBodyValue.FindStringSubmatch(e.Value)
+		if e.Key != "file" || match == nil {
+			return nil, fmt.Errorf("invalid body:file entry %q", e.Key+": "+e.Value)
+		}
+		entries = append(entries, FileBodyEntry{match[1], match[2], !e.Disabled})
+	}
+	return entries, nil
+}
+
+// NewFileBodyBlock returns the body:file block holding the entries
+func NewFileBodyBlock(entries []FileBodyEntry) *DictionaryBlock {
+	block := &DictionaryBlock{Name: "body", Type: "file"}
+	for _, e := range entries {
+		value := "@file(" + e.Path + ")"
+		if e.ContentType != "" {
+			value += " @contentType(" + e.ContentType + ")"
+		}
+		block.Content = append(block.Content, DictionaryElement{"file", value, !e.Selected})
+	}
+	return block
+}
@@ -0,0 +1,35 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFileBody(t *testing.T) {
+	simpleFile := `body:file {
+  file: @file(fixtures/avatar.png) @contentType(image/png)
+  ~file: @file(fixtures/large file.bin) @contentType(application/octet-stream)
+}`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	entries, err := read[0].(*DictionaryBlock).FileBodyEntries()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []FileBodyEntry{
+		{"fixtures/avatar.png", "image/png", true},
+		{"fixtures/large file.bin", "application/octet-stream", false},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+	encoded, err := Write([]ContentBlock{NewFileBodyBlock(entries)})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(encoded) != simpleFile {
+		t.Fatalf("unexpected encoding:\n%s", string(encoded))
+	}
+}
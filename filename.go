@@ -0,0 +1,46 @@
+package bru
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// invalidFilenameChars matches the characters the Bruno app replaces in file names
+var invalidFilenameChars = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1F]`)
+
+var (
+	leadingFilenameJunk  = regexp.MustCompile(`^[\s\-]+`)
+	trailingFilenameJunk = regexp.MustCompile(`[.\s]+$`)
+)
+
+// reservedFilename matches the names Windows reserves for devices, whatever their extension
+var reservedFilename = regexp.MustCompile(`(?i)^(con|prn|aux|nul|com[1-9]|lpt[1-9])(\.|$)`)
+
+// SafeFilename converts a request or folder name to a file name the same way the Bruno app does:
+// characters invalid on common filesystems are replaced by '-', leading spaces and dashes
+// as well as trailing spaces and dots, which Windows drops, are removed.
+// Names Windows reserves for devices, such as CON or nul.txt, are prefixed with '_'.
+// The returned name has no extension, append ".bru" for a request file.
+func SafeFilename(name string) string {
+	name = invalidFilenameChars.ReplaceAllString(name, "-")
+	name = leadingFilenameJunk.ReplaceAllString(name, "")
+	name = trailingFilenameJunk.ReplaceAllString(name, "")
+	if reservedFilename.MatchString(name) {
+		name = "_" + name
+	}
+	return name
+}
+
+// UniqueFilename returns SafeFilename(name), suffixed with _1, _2... until it is not in taken.
+// Names are compared case-insensitively, as they would collide on case-insensitive filesystems.
+// The returned name is added to taken.
+func UniqueFilename(name string, taken map[string]bool) string {
+	base := SafeFilename(name)
+	candidate := base
+	for i := 1; taken[strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s_%d", base, i)
+	}
+	taken[strings.ToLower(candidate)] = true
+	return candidate
+}
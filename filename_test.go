@@ -0,0 +1,42 @@
+package bru
+
+import "testing"
+
+func TestSafeFilename(t *testing.T) {
+	cases := map[string]string{
+		"User Info":            "User Info",
+		"GET /users/:id":       "GET -users--id",
+		" - Search? repos. ":   "Search- repos",
+		"a<b>c\"d|e*f\\g\x01h": "a-b-c-d-e-f-g-h",
+		"Draft. . ":            "Draft",
+		"CON":                  "_CON",
+		"nul.txt":              "_nul.txt",
+		"com1 ":                "_com1",
+		"Console":              "Console",
+		"LPT10":                "LPT10",
+	}
+	for name, expected := range cases {
+		if got := SafeFilename(name); got != expected {
+			t.Errorf("SafeFilename(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func TestUniqueFilename(t *testing.T) {
+	// In order, each name taking the file name it gets
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{"User Info", "User Info"},
+		{"User Info", "User Info_1"},
+		{"user info", "user info_2"},
+		{"User: Info", "User- Info"},
+	}
+	taken := map[string]bool{}
+	for _, c := range cases {
+		if got := UniqueFilename(c.name, taken); got != c.expected {
+			t.Errorf("UniqueFilename(%q) = %q, expected %q", c.name, got, c.expected)
+		}
+	}
+}
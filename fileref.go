@@ -0,0 +1,87 @@
+package bru
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// fileRef matches a @file(<paths>) value, Bruno separating the files of a multipart entry with '|'
+var fileRef = regexp.MustCompile(`@file\(([^)]*)\)`)
+
+// A FileReference is a file a collection refers to, such as the file sent as a request body
+type FileReference struct {
+	Path   string // path as written, relative to the collection unless absolute
+	Source string // path of the request in the collection, or bruno.json for client certificates
+	Block  string // tag of the block, such as body:file, empty for bruno.json
+	Exists bool   // whether the file exists
+}
+
+// FileReferences returns the files the collection refers to: the @file(...) values of the enabled entries
+// of the requests, such as the ones of body:file and body:multipart-form blocks, and the client certificates
+// and keys of bruno.json. Relative paths are checked in the filesystem the collection was loaded from.
+func (c *Collection) FileReferences() ([]FileReference, error) {
+	var refs []FileReference
+	for _, r := range c.Find(func(*Request) bool { return true }) {
+		for _, b := range r.Blocks {
+			for _, e := range dictionaryContent(b) {
+				if e.Disabled {
+					continue
+				}
+				for _, m := range fileRef.FindAllStringSubmatch(e.Value, -1) {
+					for _, p := range strings.Split(m[1], "|") {
+						if p != "" {
+							refs = append(refs, FileReference{p, r.Path, tagOf(b), c.fileExists(p)})
+						}
+					}
+				}
+			}
+		}
+	}
+	if c.Config == nil {
+		return refs, nil
+	}
+	var config struct {
+		ClientCertificates struct {
+			Certs []struct {
+				CertFilePath string `json:"certFilePath"`
+				KeyFilePath  string `json:"keyFilePath"`
+				PfxFilePath  string `json:"pfxFilePath"`
+			} `json:"certs"`
+		} `json:"clientCertificates"`
+	}
+	if err := json.Unmarshal(c.Config, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", collectionConfigFile, err)
+	}
+	for _, cert := range config.ClientCertificates.Certs {
+		for _, p := range []string{cert.CertFilePath, cert.KeyFilePath, cert.PfxFilePath} {
+			if p != "" {
+				refs = append(refs, FileReference{p, collectionConfigFile, "", c.fileExists(p)})
+			}
+		}
+	}
+	return refs, nil
+}
+
+// fileExists reports whether the file at p, relative to the collection unless absolute, exists
+func (c *Collection) fileExists(p string) bool {
+	if filepath.IsAbs(p) {
+		_, err := os.Stat(p)
+		return err == nil
+	}
+	if rel := path.Clean(filepath.ToSlash(p)); c.fsys != nil && fs.ValidPath(rel) {
+		_, err := fs.Stat(c.fsys, rel)
+		return err == nil
+	}
+	if c.Path != "" {
+		// Outside of the collection
+		_, err := os.Stat(filepath.Join(c.Path, filepath.FromSlash(p)))
+		return err == nil
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFileReferences(t *testing.T) {
+	c, err := LoadCollectionFS(fstest.MapFS{
+		"bruno.json": {Data: []byte(`{"version": "1", "name": "Files", "type": "collection",
+  "clientCertificates": {"enabled": true, "certs": [{"domain": "api.test", "type": "cert", "certFilePath": "certs/client.pem", "keyFilePath": "certs/client.key"}]}}`)},
+		"certs/client.pem":    {Data: []byte("cert")},
+		"fixtures/avatar.png": {Data: []byte("png")},
+		"Upload.bru": {Data: []byte("post {\n  url: /upload\n  body: file\n}\n\nbody:file {\n  file: @file(fixtures/avatar.png) @contentType(image/png)\n" +
+			"  ~file: @file(fixtures/unused.bin)\n}")},
+		"Users/Form.bru": {Data: []byte("post {\n  url: /form\n  body: multipartForm\n}\n\nbody:multipart-form {\n  name: toto\n  docs: @file(fixtures/avatar.png|fixtures/missing.pdf)\n}")},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	refs, err := c.FileReferences()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []FileReference{
+		{"fixtures/avatar.png", "Upload.bru", "body:file", true},
+		{"fixtures/avatar.png", "Users/Form.bru", "body:multipart-form", true},
+		{"fixtures/missing.pdf", "Users/Form.bru", "body:multipart-form", false},
+		{"certs/client.pem", "bruno.json", "", true},
+		{"certs/client.key", "bruno.json", "", false},
+	}
+	if !reflect.DeepEqual(refs, expected) {
+		t.Fatalf("unexpected references %v", refs)
+	}
+}
@@ -0,0 +1,93 @@
+package bru
+
+import (
+	"path"
+	"strings"
+)
+
+// A RequestFilter selects requests in Collection.Find
+type RequestFilter func(r *Request) bool
+
+// Find returns the requests of the collection selected by filter, in the order of its SortPolicy,
+// each folder listing its requests before the ones of its sub folders
+func (c *Collection) Find(filter RequestFilter) []*Request {
+	var found []*Request
+	var walk func(folders []*Folder, requests []*Request)
+	walk = func(folders []*Folder, requests []*Request) {
+		for _, r := range requests {
+			if filter(r) {
+				found = append(found, r)
+			}
+		}
+		for _, f := range folders {
+			walk(f.Folders, f.Requests)
+		}
+	}
+	walk(c.Folders, c.Requests)
+	return found
+}
+
+// MatchName selects the requests whose name matches the glob pattern, with the syntax of path.Match.
+// An invalid pattern matches no request.
+func MatchName(pattern string) RequestFilter {
+	return func(r *Request) bool {
+		matched, _ := path.Match(pattern, r.Name)
+		return matched
+	}
+}
+
+// MatchURL selects the requests whose URL, as written, contains s
+func MatchURL(s string) RequestFilter {
+	return func(r *Request) bool {
+		return strings.Contains(r.URL(), s)
+	}
+}
+
+// MatchMethod selects the requests with the given HTTP method, compared case-insensitively
+func MatchMethod(method string) RequestFilter {
+	return func(r *Request) bool {
+		return r.Method() != "" && strings.EqualFold(r.Method(), method)
+	}
+}
+
+// MatchTag selects the requests having tag in the tags entry of their meta block
+func MatchTag(tag string) RequestFilter {
+	return func(r *Request) bool {
+		for _, t := range r.Tags() {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchAll selects the requests selected by all the filters
+func MatchAll(filters ...RequestFilter) RequestFilter {
+	return func(r *Request) bool {
+		for _, f := range filters {
+			if !f(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Tags returns the tags of the request, read from the comma separated tags entry of its meta block.
+// Surrounding brackets are allowed, as in tags: [smoke, auth].
+func (r *Request) Tags() []string {
+	meta, ok := FindBlock(r.Blocks, "meta").(*DictionaryBlock)
+	if !ok {
+		return nil
+	}
+	value, _ := meta.Get("tags")
+	value = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "["), "]")
+	var tags []string
+	for _, t := range strings.Split(value, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,45 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	c, err := LoadCollection("testFiles")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	paths := func(requests []*Request) []string {
+		var p []string
+		for _, r := range requests {
+			p = append(p, r.Path)
+		}
+		return p
+	}
+	if found := paths(c.Find(MatchName("User *"))); !reflect.DeepEqual(found, []string{"User/User Info.bru", "User/User Repos.bru"}) {
+		t.Fatalf("unexpected requests %v", found)
+	}
+	if found := paths(c.Find(MatchURL("/repos/"))); len(found) != 2 {
+		t.Fatalf("unexpected requests %v", found)
+	}
+	if found := c.Find(MatchMethod("get")); len(found) != 5 {
+		t.Fatalf("unexpected requests %v", paths(found))
+	}
+	if found := c.Find(MatchMethod("post")); len(found) != 0 {
+		t.Fatalf("unexpected requests %v", paths(found))
+	}
+	if found := c.Find(MatchName("[")); len(found) != 0 {
+		t.Fatalf("invalid pattern should match nothing, got %v", paths(found))
+	}
+
+	info := c.Folders[1].Requests[0]
+	info.Blocks[0].(*DictionaryBlock).Content = append(info.Blocks[0].(*DictionaryBlock).Content, DictionaryElement{"tags", "[smoke, users]", false})
+	if !reflect.DeepEqual(info.Tags(), []string{"smoke", "users"}) {
+		t.Fatalf("unexpected tags %v", info.Tags())
+	}
+	found := paths(c.Find(MatchAll(MatchTag("smoke"), MatchMethod("GET"))))
+	if !reflect.DeepEqual(found, []string{"User/User Info.bru"}) {
+		t.Fatalf("unexpected requests %v", found)
+	}
+}
@@ -0,0 +1,49 @@
+package bru
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FormatError renders an error returned when reading src for a terminal: syntax errors are followed
+// by the line of src they occurred on, with a caret under the offending column.
+// Joined errors, as returned with WithAllErrors, are rendered one after the other.
+// Other errors are rendered as their message.
+func FormatError(err error, src []byte) string {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var parts []string
+		for _, e := range joined.Unwrap() {
+			parts = append(parts, FormatError(e, src))
+		}
+		return strings.Join(parts, "\n")
+	}
+	var syntax *SyntaxError
+	if !errors.As(err, &syntax) || syntax.Line == 0 {
+		return err.Error()
+	}
+	// Positions are those of the normalized input
+	lines := strings.Split(string(normalizeInput(src)), "\n")
+	if int(syntax.Line) > len(lines) {
+		return err.Error()
+	}
+	line := lines[syntax.Line-1]
+	col := int(syntax.Column) - 1
+	if col > len(line) {
+		col = len(line)
+	}
+	if col < 0 {
+		col = 0
+	}
+	// Tabs are kept so that the caret lines up with the source whatever their width
+	var caret strings.Builder
+	for i := 0; i < col; i++ {
+		if line[i] == '\t' {
+			caret.WriteByte('\t')
+		} else {
+			caret.WriteByte(' ')
+		}
+	}
+	gutter := fmt.Sprint(syntax.Line)
+	return fmt.Sprintf("%s\n %s | %s\n %s | %s^", err, gutter, line, strings.Repeat(" ", len(gutter)), caret.String())
+}
@@ -0,0 +1,34 @@
+package bru
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatError(t *testing.T) {
+	src := []byte("meta {\n  name: toto\n\tseq 1\n}")
+	_, err := Read(src)
+	if err == nil {
+		t.Fatal("should have failed")
+	}
+	expected := err.Error() + "\n 3 | \tseq 1\n   | \t     ^"
+	if formatted := FormatError(err, src); formatted != expected {
+		t.Fatalf("unexpected rendering:\n%s", formatted)
+	}
+	if formatted := FormatError(errors.New("other"), src); formatted != "other" {
+		t.Fatalf("unexpected rendering:\n%s", formatted)
+	}
+}
+
+func TestFormatAllErrors(t *testing.T) {
+	src := []byte("meta {\n  name: toto\n  seq 1\n}\n\nget {\n  url\n}\n")
+	_, err := Read(src, WithAllErrors())
+	if err == nil {
+		t.Fatal("should have failed")
+	}
+	formatted := FormatError(err, src)
+	syntaxErrors := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(syntaxErrors) != 2 || formatted != FormatError(syntaxErrors[0], src)+"\n"+FormatError(syntaxErrors[1], src) {
+		t.Fatalf("unexpected rendering:\n%s", formatted)
+	}
+}
@@ -0,0 +1,86 @@
+package bru
+
+import (
+	"errors"
+	"strings"
+)
+
+// A GenericBlock is a block whose tag is unknown to the package, read when WithLenientTags is used.
+// Its kind is inferred from its delimiters and content: brackets are an array,
+// braces are a dictionary if every line looks like a key: value pair, and text otherwise.
+type GenericBlock struct {
+	Name       string
+	Type       string
+	Kind       BlockKind
+	Dictionary []DictionaryElement // content of a DictionaryKind block
+	Text       string              // content of a TextKind block
+	Array      []ArrayElement      // content of an ArrayKind block
+	Comments   []Comment
+	// positions locate the content in the input the block was read from
+	positions blockPositions
+}
+
+func (t *GenericBlock) GetType() string {
+	return t.Type
+}
+
+func (t *GenericBlock) GetName() string {
+	return t.Name
+}
+
+// SetContent sets the content of the block, the kind following the type of content
+func (t *GenericBlock) SetContent(content any) error {
+	switch c := content.(type) {
+	case []DictionaryElement:
+		t.Kind, t.Dictionary = DictionaryKind, c
+	case string:
+		t.Kind, t.Text = TextKind, c
+	case []ArrayElement:
+		t.Kind, t.Array = ArrayKind, c
+	case []string:
+		a := &ArrayBlock{}
+		_ = a.SetContent(c)
+		t.Kind, t.Array = ArrayKind, a.Content
+	default:
+		return errors.New("wrong type to set for generic block")
+	}
+	return nil
+}
+
+// Block returns the block of the inferred kind holding the content
+func (t *GenericBlock) Block() ContentBlock {
+	switch t.Kind {
+	case DictionaryKind:
+		return &DictionaryBlock{Name: t.Name, Type: t.Type, Content: t.Dictionary, Comments: t.Comments, positions: t.positions}
+	case ArrayKind:
+		return &ArrayBlock{Name: t.Name, Type: t.Type, Content: t.Array, Comments: t.Comments, positions: t.positions}
+	}
+	return &TextBlock{Name: t.Name, Type: t.Type, Content: t.Text, Comments: t.Comments, positions: t.positions}
+}
+
+// setBracedContent infers the kind of the content read between braces
+func (t *GenericBlock) setBracedContent(text string) {
+	if dic, ok := parseDictionaryText(text); ok {
+		t.Kind, t.Dictionary = DictionaryKind, dic
+		return
+	}
+	t.Kind, t.Text = TextKind, text
+}
+
+// parseDictionaryText reads text as dictionary lines, ok is false if a line is not a key: value pair
+func parseDictionaryText(text string) ([]DictionaryElement, bool) {
+	var dic []DictionaryElement
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found || key == "" || strings.ContainsAny(key, " \t\"'{}[]()") {
+			return nil, false
+		}
+		key, disabled := cutDisabledPrefix(key)
+		dic = append(dic, DictionaryElement{key, strings.TrimSpace(value), disabled})
+	}
+	return dic, true
+}
@@ -0,0 +1,59 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLenientTags(t *testing.T) {
+	simpleFile := `meta {
+  name: toto
+}
+
+grpc {
+  url: localhost:50051
+  ~method: Greeter/SayHello
+}
+
+body:grpc {
+  {
+    "name": "world"
+  }
+}
+
+vars:unknown [
+  toto,
+  ~titi
+]`
+	if _, err := Read([]byte(simpleFile)); err == nil {
+		t.Fatal("unknown tags should fail without lenient mode")
+	}
+	read, err := Read([]byte(simpleFile), WithLenientTags())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, ok := read[0].(*DictionaryBlock); !ok {
+		t.Fatalf("known tags should keep their block type, got %T", read[0])
+	}
+	grpc := read[1].(*GenericBlock)
+	if grpc.Kind != DictionaryKind || !reflect.DeepEqual(grpc.Dictionary, []DictionaryElement{
+		{"url", "localhost:50051", false}, {"method", "Greeter/SayHello", true},
+	}) {
+		t.Fatalf("unexpected dictionary generic block %+v", grpc)
+	}
+	body := read[2].(*GenericBlock)
+	if body.Kind != TextKind || body.Name != "body" || body.Type != "grpc" {
+		t.Fatalf("unexpected text generic block %+v", body)
+	}
+	vars := read[3].(*GenericBlock)
+	if vars.Kind != ArrayKind || len(vars.Array) != 2 {
+		t.Fatalf("unexpected array generic block %+v", vars)
+	}
+	encoded, err := Write(read)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(encoded) != simpleFile {
+		t.Fatalf("unexpected encoding:\n%s", string(encoded))
+	}
+}
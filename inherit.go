@@ -0,0 +1,249 @@
+package bru
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A SourceKind is the kind of file an inherited value is set in
+type SourceKind int
+
+// The places a request inherits values from
+const (
+	SourceCollection  SourceKind = iota // collection.bru
+	SourceFolder                        // folder.bru of a folder containing the request
+	SourceRequest                       // the request file itself
+	SourceEnvironment                   // the selected environment
+)
+
+// A Source is where an inherited value is set
+type Source struct {
+	Kind SourceKind
+	Path string // path of the folder or request, name of the environment, empty for the collection
+}
+
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceCollection:
+		return "collection"
+	case SourceFolder:
+		return "folder " + s.Path
+	case SourceRequest:
+		return "request " + s.Path
+	case SourceEnvironment:
+		return "environment " + s.Path
+	}
+	return fmt.Sprintf("SourceKind(%d) %s", int(s.Kind), s.Path)
+}
+
+// errRequestNotFound is returned when a request is not part of the collection it is resolved in
+var errRequestNotFound = errors.New("request is not part of the collection")
+
+// inheritLevel holds the blocks of a file a request inherits from
+type inheritLevel struct {
+	source Source
+	blocks []ContentBlock
+}
+
+// levels returns the files r inherits from: the collection, the folders containing r from the outermost, and r itself
+func (c *Collection) levels(r *Request) ([]inheritLevel, error) {
+	folders, ok := findRequest(c.Folders, c.Requests, r)
+	if !ok {
+		return nil, errRequestNotFound
+	}
+	levels := []inheritLevel{{Source{SourceCollection, ""}, c.Blocks}}
+	for _, f := range folders {
+		levels = append(levels, inheritLevel{Source{SourceFolder, f.Path}, f.Blocks})
+	}
+	return append(levels, inheritLevel{Source{SourceRequest, r.Path}, r.Blocks}), nil
+}
+
+// Request returns the request whose file is at path in the collection
+func (c *Collection) Request(path string) (*Request, bool) {
+	var walk func(folders []*Folder, requests []*Request) *Request
+	walk = func(folders []*Folder, requests []*Request) *Request {
+		for _, r := range requests {
+			if r.Path == path {
+				return r
+			}
+		}
+		for _, f := range folders {
+			if r := walk(f.Folders, f.Requests); r != nil {
+				return r
+			}
+		}
+		return nil
+	}
+	r := walk(c.Folders, c.Requests)
+	return r, r != nil
+}
+
+// findRequest returns the folders leading to r, from the outermost
+func findRequest(folders []*Folder, requests []*Request, r *Request) ([]*Folder, bool) {
+	for _, candidate := range requests {
+		if candidate == r {
+			return nil, true
+		}
+	}
+	for _, f := range folders {
+		if path, ok := findRequest(f.Folders, f.Requests, r); ok {
+			return append([]*Folder{f}, path...), true
+		}
+	}
+	return nil, false
+}
+
+// EffectiveSettings returns the settings applying to r: the enabled entries of the settings blocks
+// of the collection, of the folders containing r and of r itself, the closest to r taking precedence.
+// The source of every setting is returned by key.
+func (c *Collection) EffectiveSettings(r *Request) (Settings, map[string]Source, error) {
+	levels, err := c.levels(r)
+	if err != nil {
+		return Settings{}, nil, err
+	}
+	merged := &DictionaryBlock{Name: "settings"}
+	sources := map[string]Source{}
+	for _, l := range levels {
+		block, ok := FindBlock(l.blocks, "settings").(*DictionaryBlock)
+		if !ok {
+			continue
+		}
+		// Checked on their own to report the file of an invalid value
+		if _, err := block.Settings(); err != nil {
+			return Settings{}, nil, fmt.Errorf("%s: %w", l.source, err)
+		}
+		for _, e := range block.Content {
+			if e.Disabled {
+				continue
+			}
+			if _, ok := sources[e.Key]; ok {
+				for i := range merged.Content {
+					if merged.Content[i].Key == e.Key {
+						merged.Content[i] = e
+					}
+				}
+			} else {
+				merged.Content = append(merged.Content, e)
+			}
+			sources[e.Key] = l.source
+		}
+	}
+	settings, err := merged.Settings()
+	return settings, sources, err
+}
+
+// An EffectiveVar is the value of a variable at a request, with where it is set
+type EffectiveVar struct {
+	Value  string
+	Source Source
+}
+
+// EffectiveVars returns the variables defined at the request whose file is at requestPath,
+// following the precedence of Bruno from the lowest: the vars:pre-request block of the collection,
+// the variables of env, which may be nil, the vars:pre-request blocks of the folders containing
+// the request from the outermost, and the vars:pre-request block of the request.
+// Only enabled variables are taken into account, the values of secret variables are empty.
+// Variables set while running, such as post-response variables, are not known.
+func (c *Collection) EffectiveVars(requestPath string, env *Environment) (map[string]EffectiveVar, error) {
+	r, ok := c.Request(requestPath)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", requestPath, errRequestNotFound)
+	}
+	levels, err := c.levels(r)
+	if err != nil {
+		return nil, err
+	}
+	vars := map[string]EffectiveVar{}
+	set := func(elements []DictionaryElement, source Source) {
+		for _, e := range elements {
+			if !e.Disabled {
+				vars[e.Key] = EffectiveVar{e.Value, source}
+			}
+		}
+	}
+	set(PreRequestVars(levels[0].blocks), levels[0].source)
+	if env != nil {
+		source := Source{SourceEnvironment, env.Name}
+		for _, v := range env.Variables {
+			if !v.Disabled {
+				vars[v.Name] = EffectiveVar{v.Value, source}
+			}
+		}
+	}
+	for _, l := range levels[1:] {
+		set(PreRequestVars(l.blocks), l.source)
+	}
+	return vars, nil
+}
+
+// An EffectiveHeader is a header sent with a request, with where it is set
+type EffectiveHeader struct {
+	Name   string
+	Value  string
+	Source Source
+}
+
+// EffectiveHeaders returns the headers sent with the request whose file is at requestPath:
+// the enabled entries of the headers blocks of the collection, of the folders containing the request
+// and of the request itself, the closest to the request taking precedence.
+// Header names are compared case-insensitively, a header keeping the position it is first set at.
+func (c *Collection) EffectiveHeaders(requestPath string) ([]EffectiveHeader, error) {
+	r, ok := c.Request(requestPath)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", requestPath, errRequestNotFound)
+	}
+	levels, err := c.levels(r)
+	if err != nil {
+		return nil, err
+	}
+	// Merged as by Resolve, the source of a header being the last level setting it
+	var merged []DictionaryElement
+	sources := map[string]Source{}
+	for _, l := range levels {
+		elements := dictionaryContent(FindBlock(l.blocks, "headers"))
+		merged = mergeElements(merged, elements, true)
+		for _, e := range elements {
+			if !e.Disabled {
+				sources[strings.ToLower(e.Key)] = l.source
+			}
+		}
+	}
+	headers := make([]EffectiveHeader, len(merged))
+	for i, h := range merged {
+		headers[i] = EffectiveHeader{h.Key, h.Value, sources[strings.ToLower(h.Key)]}
+	}
+	return headers, nil
+}
+
+// An EffectiveAuth is the auth a request is sent with, with where it is set
+type EffectiveAuth struct {
+	Mode   string       // auth mode, such as bearer, none if the request has no auth
+	Block  ContentBlock // auth block of the mode, such as auth:bearer, nil if there is none
+	Source Source
+}
+
+// EffectiveAuth returns the auth of the request whose file is at requestPath.
+// Like with Resolve, an auth mode set to inherit is replaced by the mode of the closest folder
+// or of the collection setting one, the source being where this mode is set.
+func (c *Collection) EffectiveAuth(requestPath string) (EffectiveAuth, error) {
+	r, ok := c.Request(requestPath)
+	if !ok {
+		return EffectiveAuth{}, fmt.Errorf("%s: %w", requestPath, errRequestNotFound)
+	}
+	levels, err := c.levels(r)
+	if err != nil {
+		return EffectiveAuth{}, err
+	}
+	mode := "none"
+	if method, ok := FindBlock(r.Blocks, strings.ToLower(r.Method())).(*DictionaryBlock); ok {
+		if m, _ := method.Get("auth"); m != "" {
+			mode = m
+		}
+	}
+	if mode == "inherit" {
+		mode, block, source := inheritedAuth(levels[:len(levels)-1])
+		return EffectiveAuth{mode, block, source}, nil
+	}
+	return EffectiveAuth{mode, FindBlock(r.Blocks, "auth:"+mode), Source{SourceRequest, r.Path}}, nil
+}
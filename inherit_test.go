@@ -0,0 +1,160 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func inheritCollection(t *testing.T) *Collection {
+	c, err := LoadCollectionFS(fstest.MapFS{
+		"collection.bru":   {Data: []byte("settings {\n  timeout: 1000\n  encodeUrl: true\n}\n\nheaders {\n  Accept: application/json\n}")},
+		"Users/folder.bru": {Data: []byte("settings {\n  timeout: 5000\n  ~encodeUrl: false\n}\n\nvars:pre-request {\n  page: 1\n}")},
+		"Users/Get User.bru": {Data: []byte("meta {\n  name: Get User\n}\n\nget {\n  url: {{baseUrl}}/users/{{id}}\n}\n\n" +
+			"settings {\n  followRedirects: false\n}\n\nvars:pre-request {\n  id: 1\n}")},
+		"Health.bru": {Data: []byte("get {\n  url: {{baseUrl}}/health\n}")},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return c
+}
+
+func TestEffectiveSettings(t *testing.T) {
+	c := inheritCollection(t)
+	settings, sources, err := c.EffectiveSettings(c.Folders[0].Requests[0])
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if *settings.Timeout != 5000 || !*settings.EncodeURL || *settings.FollowRedirects || settings.MaxRedirects != nil {
+		t.Fatalf("unexpected settings %+v", settings.Block())
+	}
+	expected := map[string]Source{
+		"timeout":         {SourceFolder, "Users"},
+		"encodeUrl":       {SourceCollection, ""},
+		"followRedirects": {SourceRequest, "Users/Get User.bru"},
+	}
+	if !reflect.DeepEqual(sources, expected) {
+		t.Fatalf("unexpected sources %v", sources)
+	}
+	settings, sources, err = c.EffectiveSettings(c.Requests[0])
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if *settings.Timeout != 1000 || sources["timeout"].String() != "collection" {
+		t.Fatalf("unexpected settings %+v", settings.Block())
+	}
+	if _, _, err := c.EffectiveSettings(&Request{}); err == nil {
+		t.Fatal("should have failed for a request outside of the collection")
+	}
+	c.Folders[0].Blocks[0].(*DictionaryBlock).Content[0].Value = "soon"
+	if _, _, err := c.EffectiveSettings(c.Folders[0].Requests[0]); err == nil || err.Error() != `folder Users: settings timeout: invalid integer "soon"` {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestEffectiveVars(t *testing.T) {
+	c := inheritCollection(t)
+	c.Blocks = SetPreRequestVars(c.Blocks, []DictionaryElement{{"baseUrl", "https://prod", false}, {"id", "0", false}, {"debug", "true", true}})
+	env := &Environment{Name: "Local", Variables: []EnvironmentVariable{
+		{"baseUrl", "http://localhost", false, false},
+		{"token", "", false, true},
+		{"page", "0", true, false},
+	}}
+	vars, err := c.EffectiveVars("Users/Get User.bru", env)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := map[string]EffectiveVar{
+		"baseUrl": {"http://localhost", Source{SourceEnvironment, "Local"}},
+		"token":   {"", Source{SourceEnvironment, "Local"}},
+		"page":    {"1", Source{SourceFolder, "Users"}},
+		"id":      {"1", Source{SourceRequest, "Users/Get User.bru"}},
+	}
+	if !reflect.DeepEqual(vars, expected) {
+		t.Fatalf("unexpected vars %v", vars)
+	}
+	vars, err = c.EffectiveVars("Health.bru", nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(vars) != 2 || vars["baseUrl"].Source.String() != "collection" {
+		t.Fatalf("unexpected vars %v", vars)
+	}
+	if _, err := c.EffectiveVars("Missing.bru", nil); err == nil {
+		t.Fatal("should have failed")
+	}
+}
+
+func TestEffectiveHeaders(t *testing.T) {
+	c := inheritCollection(t)
+	c.Folders[0].Blocks = append(c.Folders[0].Blocks, &DictionaryBlock{Name: "headers", Content: []DictionaryElement{
+		{"X-Team", "users", false},
+		{"X-Debug", "true", true},
+	}})
+	c.Folders[0].Requests[0].Blocks = append(c.Folders[0].Requests[0].Blocks, &DictionaryBlock{Name: "headers", Content: []DictionaryElement{
+		{"accept", "application/xml", false},
+	}})
+	headers, err := c.EffectiveHeaders("Users/Get User.bru")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []EffectiveHeader{
+		{"accept", "application/xml", Source{SourceRequest, "Users/Get User.bru"}},
+		{"X-Team", "users", Source{SourceFolder, "Users"}},
+	}
+	if !reflect.DeepEqual(headers, expected) {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+	headers, err = c.EffectiveHeaders("Health.bru")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(headers) != 1 || headers[0].Name != "Accept" || headers[0].Source.String() != "collection" {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+	if _, err := c.EffectiveHeaders("Missing.bru"); err == nil {
+		t.Fatal("should have failed")
+	}
+}
+
+func TestEffectiveAuth(t *testing.T) {
+	c, err := LoadCollectionFS(fstest.MapFS{
+		"collection.bru":     {Data: []byte("auth {\n  mode: bearer\n}\n\nauth:bearer {\n  token: {{token}}\n}")},
+		"Users/folder.bru":   {Data: []byte("auth {\n  mode: inherit\n}")},
+		"Users/Get User.bru": {Data: []byte("get {\n  url: {{baseUrl}}/users/1\n  auth: inherit\n}")},
+		"Admin/folder.bru":   {Data: []byte("auth {\n  mode: basic\n}\n\nauth:basic {\n  username: admin\n  password: secret\n}")},
+		"Admin/List.bru":     {Data: []byte("get {\n  url: {{baseUrl}}/admin\n  auth: inherit\n}")},
+		"Admin/Own.bru":      {Data: []byte("get {\n  url: {{baseUrl}}/own\n  auth: apikey\n}\n\nauth:apikey {\n  key: k\n  value: v\n}")},
+		"Health.bru":         {Data: []byte("get {\n  url: {{baseUrl}}/health\n}")},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cases := []struct {
+		path   string
+		mode   string
+		block  bool
+		source Source
+	}{
+		{"Users/Get User.bru", "bearer", true, Source{SourceCollection, ""}},
+		{"Admin/List.bru", "basic", true, Source{SourceFolder, "Admin"}},
+		{"Admin/Own.bru", "apikey", true, Source{SourceRequest, "Admin/Own.bru"}},
+		{"Health.bru", "none", false, Source{SourceRequest, "Health.bru"}},
+	}
+	for _, tc := range cases {
+		auth, err := c.EffectiveAuth(tc.path)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if auth.Mode != tc.mode || (auth.Block != nil) != tc.block || auth.Source != tc.source {
+			t.Fatalf("unexpected auth for %s: %+v", tc.path, auth)
+		}
+		if auth.Block != nil && tagOf(auth.Block) != "auth:"+tc.mode {
+			t.Fatalf("unexpected auth block for %s: %s", tc.path, tagOf(auth.Block))
+		}
+	}
+	if _, err := c.EffectiveAuth("Missing.bru"); err == nil {
+		t.Fatal("should have failed")
+	}
+}
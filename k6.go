@@ -0,0 +1,130 @@
+package bru
+
+import (
+	"fmt"
+	"strings"
+)
+
+// K6Options selects the requests exported by ToK6 and the load of the script
+type K6Options struct {
+	// Filter selects the requests to export, all the requests of the collection if nil
+	Filter RequestFilter
+	// VUs is the number of virtual users running the script, left to k6 if 0
+	VUs int
+	// Duration is how long the script runs, such as 30s, left to k6 if empty
+	Duration string
+}
+
+// ToK6 returns a k6 load test script sending the requests of the collection selected by opts,
+// in the order of Collection.Find, each one in a group named after it and checked not to fail.
+// Requests are resolved as by Collection.Resolve, and bearer and basic auth are sent as an Authorization header.
+// Variables such as {{baseUrl}} are read from the environment of k6, given with -e baseUrl=...
+func ToK6(c *Collection, opts K6Options) (string, error) {
+	filter := opts.Filter
+	if filter == nil {
+		filter = func(*Request) bool { return true }
+	}
+	requests := c.Find(filter)
+	groups := make([]string, 0, len(requests))
+	encoding := false
+	for _, r := range requests {
+		resolved, err := c.Resolve(r)
+		if err != nil {
+			return "", fmt.Errorf("k6: %s: %w", r.Path, err)
+		}
+		s, err := newSnippetRequest(resolved)
+		if err != nil {
+			return "", fmt.Errorf("k6: %w", err)
+		}
+		headers := make([]string, 0, len(s.headers)+1)
+		for _, h := range s.headers {
+			headers = append(headers, fmt.Sprintf("%s: %s", quoteJS(h.Key), quoteK6(h.Value)))
+		}
+		if auth, ok := k6Authorization(resolved); ok {
+			headers = append(headers, fmt.Sprintf("%s: %s", quoteJS("Authorization"), auth))
+			encoding = encoding || strings.Contains(auth, "encoding.")
+		}
+		body := "null"
+		if s.hasBody {
+			body = quoteK6(s.body)
+		}
+		var g strings.Builder
+		fmt.Fprintf(&g, "  group(%s, function () {\n", quoteJS(r.Name))
+		fmt.Fprintf(&g, "    const res = http.request(%s, %s, %s", quoteJS(s.method), quoteK6(s.url), body)
+		if len(headers) > 0 {
+			g.WriteString(", {\n      headers: {\n")
+			for _, h := range headers {
+				g.WriteString("        " + h + ",\n")
+			}
+			g.WriteString("      },\n    }")
+		}
+		g.WriteString(");\n")
+		g.WriteString("    check(res, { \"status is not an error\": (r) => r.status < 400 });\n  });\n")
+		groups = append(groups, g.String())
+	}
+
+	var b strings.Builder
+	b.WriteString("import http from \"k6/http\";\nimport { check, group } from \"k6\";\n")
+	if encoding {
+		b.WriteString("import encoding from \"k6/encoding\";\n")
+	}
+	if opts.VUs > 0 || opts.Duration != "" {
+		b.WriteString("\nexport const options = {\n")
+		if opts.VUs > 0 {
+			fmt.Fprintf(&b, "  vus: %d,\n", opts.VUs)
+		}
+		if opts.Duration != "" {
+			fmt.Fprintf(&b, "  duration: %s,\n", quoteJS(opts.Duration))
+		}
+		b.WriteString("};\n")
+	}
+	b.WriteString("\nexport default function () {\n")
+	b.WriteString(strings.Join(groups, "\n"))
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// k6Authorization returns the expression of the Authorization header sent with the auth mode of the resolved request
+func k6Authorization(r *Request) (string, bool) {
+	method, ok := FindBlock(r.Blocks, strings.ToLower(r.Method())).(*DictionaryBlock)
+	if !ok {
+		return "", false
+	}
+	mode, _ := method.Get("auth")
+	block, ok := FindBlock(r.Blocks, "auth:"+mode).(*DictionaryBlock)
+	if !ok {
+		return "", false
+	}
+	switch mode {
+	case "bearer":
+		auth, err := block.BearerAuth()
+		if err != nil {
+			return "", false
+		}
+		return quoteK6("Bearer " + auth.Token), true
+	case "basic":
+		auth, err := block.BasicAuth()
+		if err != nil {
+			return "", false
+		}
+		return fmt.Sprintf("\"Basic \" + encoding.b64encode(%s)", quoteK6(auth.Username+":"+auth.Password)), true
+	}
+	return "", false
+}
+
+// quoteK6 returns a JavaScript expression of s, its {{name}} references being read from __ENV
+func quoteK6(s string) string {
+	var parts []string
+	last := 0
+	for _, m := range variableRef.FindAllStringSubmatchIndex(s, -1) {
+		if m[0] > last {
+			parts = append(parts, quoteJS(s[last:m[0]]))
+		}
+		parts = append(parts, "__ENV["+quoteJS(s[m[2]:m[3]])+"]")
+		last = m[1]
+	}
+	if last < len(s) || len(parts) == 0 {
+		parts = append(parts, quoteJS(s[last:]))
+	}
+	return strings.Join(parts, " + ")
+}
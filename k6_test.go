@@ -0,0 +1,81 @@
+package bru
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestToK6(t *testing.T) {
+	c, err := LoadCollectionFS(fstest.MapFS{
+		"collection.bru": {Data: []byte("headers {\n  X-Client: bru\n}\n\nauth {\n  mode: bearer\n}\n\nauth:bearer {\n  token: {{token}}\n}")},
+		"Users/Create User.bru": {Data: []byte("meta {\n  name: Create User\n  seq: 1\n}\n\n" +
+			"post {\n  url: {{baseUrl}}/users\n  body: json\n  auth: inherit\n}\n\nbody:json {\n  {\n    \"name\": \"{{name}}\"\n  }\n}")},
+		"Users/Get User.bru": {Data: []byte("meta {\n  name: Get User\n  seq: 2\n}\n\n" +
+			"get {\n  url: {{baseUrl}}/users/1\n  auth: basic\n}\n\nauth:basic {\n  username: toto\n  password: {{password}}\n}")},
+		"Health.bru": {Data: []byte("meta {\n  name: Health\n}\n\nget {\n  url: https://example.com/health\n}")},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	script, err := ToK6(c, K6Options{Filter: MatchURL("/users"), VUs: 10, Duration: "30s"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := `import http from "k6/http";
+import { check, group } from "k6";
+import encoding from "k6/encoding";
+
+export const options = {
+  vus: 10,
+  duration: "30s",
+};
+
+export default function () {
+  group("Create User", function () {
+    const res = http.request("POST", __ENV["baseUrl"] + "/users", "{\n  \"name\": \"" + __ENV["name"] + "\"\n}", {
+      headers: {
+        "X-Client": "bru",
+        "Content-Type": "application/json",
+        "Authorization": "Bearer " + __ENV["token"],
+      },
+    });
+    check(res, { "status is not an error": (r) => r.status < 400 });
+  });
+
+  group("Get User", function () {
+    const res = http.request("GET", __ENV["baseUrl"] + "/users/1", null, {
+      headers: {
+        "X-Client": "bru",
+        "Authorization": "Basic " + encoding.b64encode("toto:" + __ENV["password"]),
+      },
+    });
+    check(res, { "status is not an error": (r) => r.status < 400 });
+  });
+}
+`
+	if script != expected {
+		t.Fatalf("unexpected script:\n%s", script)
+	}
+
+	script, err = ToK6(c, K6Options{Filter: MatchName("Health")})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected = `import http from "k6/http";
+import { check, group } from "k6";
+
+export default function () {
+  group("Health", function () {
+    const res = http.request("GET", "https://example.com/health", null, {
+      headers: {
+        "X-Client": "bru",
+      },
+    });
+    check(res, { "status is not an error": (r) => r.status < 400 });
+  });
+}
+`
+	if script != expected {
+		t.Fatalf("unexpected script:\n%s", script)
+	}
+}
@@ -0,0 +1,25 @@
+package bru
+
+import "strings"
+
+var (
+	keyQuoter   = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	keyUnquoter = strings.NewReplacer(`\\`, `\`, `\"`, `"`)
+)
+
+// quoteKey quotes a dictionary key that would not be read back as is,
+// such as a key containing a colon or starting like a disabled entry, a comment or a block end
+func quoteKey(key string) string {
+	if key == "" || !strings.Contains(key, ":") && !strings.ContainsAny(key[:1], `~"#/}`) {
+		return key
+	}
+	return `"` + keyQuoter.Replace(key) + `"`
+}
+
+// unquoteKey returns the content of a quoted dictionary key, other keys are returned unchanged
+func unquoteKey(key string) string {
+	if len(key) < 2 || key[0] != '"' || key[len(key)-1] != '"' {
+		return key
+	}
+	return keyUnquoter.Replace(key[1 : len(key)-1])
+}
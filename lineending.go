@@ -0,0 +1,29 @@
+package bru
+
+import (
+	"bytes"
+	"strings"
+)
+
+// bom is the UTF-8 byte order mark some editors write at the start of files
+var bom = []byte("\xef\xbb\xbf")
+
+// normalizeInput strips a leading UTF-8 byte order mark and turns CRLF line endings into LF,
+// so that files saved on Windows read the same as the others
+func normalizeInput(data []byte) []byte {
+	data = bytes.TrimPrefix(data, bom)
+	if bytes.IndexByte(data, '\r') < 0 {
+		return data
+	}
+	return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+}
+
+// UsesCRLF reports whether data has CRLF line endings, to write it back the same way with WithCRLF
+func UsesCRLF(data []byte) bool {
+	return bytes.Contains(data, []byte("\r\n"))
+}
+
+// toCRLF turns the LF line endings of s into CRLF
+func toCRLF(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+}
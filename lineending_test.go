@@ -0,0 +1,88 @@
+package bru
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const lineEndingFile = `meta {
+  name: User Info
+  seq: 1
+}
+
+body:json {
+  {
+    "hello": "world"
+  }
+}
+
+vars:secret [
+  access_key,
+  access_secret
+]`
+
+func TestReadCRLF(t *testing.T) {
+	lf, err := Read([]byte(lineEndingFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	crlf, err := Read([]byte(strings.ReplaceAll(lineEndingFile, "\n", "\r\n")))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(lf, crlf) {
+		t.Fatalf("CRLF file read differently: %v", crlf)
+	}
+}
+
+func TestReadBOM(t *testing.T) {
+	lf, err := Read([]byte(lineEndingFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	withBOM, err := Read([]byte("\xef\xbb\xbf" + strings.ReplaceAll(lineEndingFile, "\n", "\r\n")))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(lf, withBOM) {
+		t.Fatalf("file with BOM read differently: %v", withBOM)
+	}
+}
+
+func TestWriteCRLF(t *testing.T) {
+	data := []byte(strings.ReplaceAll(lineEndingFile, "\n", "\r\n"))
+	if !UsesCRLF(data) || UsesCRLF([]byte(lineEndingFile)) {
+		t.Fatal("wrong line ending detection")
+	}
+	read, err := Read(data)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	written, err := Write(read, WithCRLF(UsesCRLF(data)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(written) != string(data) {
+		t.Fatalf("line endings not kept: %q", written)
+	}
+}
+
+func TestTokenizerCRLF(t *testing.T) {
+	tok := NewTokenizer([]byte("\xef\xbb\xbfmeta {\r\n  seq: 1\r\n}\r\n"))
+	var values []string
+	for {
+		token, err := tok.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		values = append(values, token.Value)
+	}
+	if !reflect.DeepEqual(values, []string{"meta", "seq", "1", ""}) {
+		t.Fatalf("unexpected tokens %q", values)
+	}
+}
@@ -0,0 +1,146 @@
+package bru
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// ManifestFile is the name of the manifest in a collection directory, which is never listed in it
+const ManifestFile = "manifest.json"
+
+// manifest is the content of a manifest.json file
+type manifest struct {
+	Files []manifestEntry `json:"files"`
+	// Signature is the ed25519 signature of the JSON encoding of Files
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// manifestEntry is a file listed in a manifest
+type manifestEntry struct {
+	Path   string `json:"path"` // relative to the collection, with forward slashes
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest returns the manifest.json content listing every file of the collection with its SHA-256 hash,
+// read from the directory or fs.FS it was loaded from, or saved to.
+// Hidden directories, node_modules and the ignore list of bruno.json are skipped, as by LoadCollection.
+// Symbolic links are rejected.
+// If key is not nil, the manifest is signed with it.
+func Manifest(c *Collection, key ed25519.PrivateKey) ([]byte, error) {
+	files, err := collectionFiles(c)
+	if err != nil {
+		return nil, err
+	}
+	m := manifest{Files: files}
+	if key != nil {
+		signed, err := json.Marshal(files)
+		if err != nil {
+			return nil, err
+		}
+		m.Signature = ed25519.Sign(key, signed)
+	}
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// VerifyManifest checks that the files of the collection are exactly the files of the manifest, unchanged,
+// and no symbolic link.
+// If key is not nil, the manifest must also be signed by it.
+func VerifyManifest(c *Collection, data []byte, key ed25519.PublicKey) error {
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	if key != nil {
+		signed, err := json.Marshal(m.Files)
+		if err != nil {
+			return err
+		}
+		if len(m.Signature) == 0 {
+			return errors.New("manifest: not signed")
+		}
+		if !ed25519.Verify(key, signed, m.Signature) {
+			return errors.New("manifest: invalid signature")
+		}
+	}
+	files, err := collectionFiles(c)
+	if err != nil {
+		return err
+	}
+	expected := make(map[string]string, len(m.Files))
+	for _, f := range m.Files {
+		expected[f.Path] = f.SHA256
+	}
+	var errs []error
+	for _, f := range files {
+		hash, ok := expected[f.Path]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("manifest: %s is not listed", f.Path))
+		case hash != f.SHA256:
+			errs = append(errs, fmt.Errorf("manifest: %s was modified", f.Path))
+		}
+		delete(expected, f.Path)
+	}
+	for _, f := range m.Files {
+		if _, ok := expected[f.Path]; ok {
+			errs = append(errs, fmt.Errorf("manifest: %s is missing", f.Path))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// hashFiles returns the hashes of the files of the collection filesystem, except the ignored paths.
+// Symbolic links and other files which are not regular are rejected.
+func hashFiles(fsys fs.FS, ignore []string) ([]manifestEntry, error) {
+	var files []manifestEntry
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != "." && ignored(p, ignore) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if p != "." && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if p == ManifestFile {
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			// Not followed by LoadCollection, its target being outside of the manifest
+			return fmt.Errorf("manifest: %s is not a regular file", p)
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		files = append(files, manifestEntry{p, hex.EncodeToString(sum[:])})
+		return nil
+	})
+	return files, err
+}
+
+// collectionFiles returns the hashes of the files of the filesystem the collection was loaded from
+func collectionFiles(c *Collection) ([]manifestEntry, error) {
+	if c.fsys == nil {
+		return nil, errors.New("manifest: collection was not loaded nor saved")
+	}
+	ignore, err := c.ignore()
+	if err != nil {
+		return nil, err
+	}
+	return hashFiles(c.fsys, ignore)
+}
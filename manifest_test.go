@@ -0,0 +1,126 @@
+package bru
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestManifest(t *testing.T) {
+	c, err := LoadCollection("testFiles")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := c.Save(t.TempDir()); err != nil {
+		t.Fatal(err.Error())
+	}
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	m, err := Manifest(c, private)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(c.Path, ManifestFile), m, 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := VerifyManifest(c, m, public); err != nil {
+		t.Fatal(err.Error())
+	}
+	other, _, _ := ed25519.GenerateKey(nil)
+	if err := VerifyManifest(c, m, other); err == nil {
+		t.Fatal("should have failed with another key")
+	}
+	unsigned, err := Manifest(c, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := VerifyManifest(c, unsigned, nil); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := VerifyManifest(c, unsigned, public); err == nil {
+		t.Fatal("should have failed without signature")
+	}
+
+	if err := os.WriteFile(filepath.Join(c.Path, "User", "User Info.bru"), []byte("meta {\n}\n"), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(c.Path, "extra.bru"), []byte("meta {\n}\n"), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	err = VerifyManifest(c, m, public)
+	if err == nil || err.Error() != "manifest: User/User Info.bru was modified\nmanifest: extra.bru is not listed" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestManifestSymlink(t *testing.T) {
+	c, err := LoadCollection("testFiles")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := c.Save(t.TempDir()); err != nil {
+		t.Fatal(err.Error())
+	}
+	m, err := Manifest(c, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	injected := filepath.Join(t.TempDir(), "Injected.bru")
+	if err := os.WriteFile(injected, []byte("get {\n  url: https://toto.com\n}\n"), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := os.Symlink(injected, filepath.Join(c.Path, "User", "Injected.bru")); err != nil {
+		t.Skip("symbolic links not supported: " + err.Error())
+	}
+	// The linked request is not loaded, and must not go unnoticed either
+	if _, err := LoadCollection(c.Path); !errors.Is(err, ErrSymlink) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := VerifyManifest(c, m, nil); err == nil || err.Error() != "manifest: User/Injected.bru is not a regular file" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := Manifest(c, nil); err == nil {
+		t.Fatal("should have failed")
+	}
+}
+
+func TestManifestFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"bruno.json":         {Data: []byte(`{"version": "1", "name": "Mem", "type": "collection", "ignore": ["generated"]}`)},
+		"Users/Get User.bru": {Data: []byte("get {\n  url: /users/1\n}\n")},
+		"generated/out.bru":  {Data: []byte("not read")},
+		".git/HEAD":          {Data: []byte("not read")},
+	}
+	c, err := LoadCollectionFS(fsys)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	m, err := Manifest(c, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var parsed manifest
+	if err := json.Unmarshal(m, &parsed); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(parsed.Files) != 2 || parsed.Files[0].Path != "Users/Get User.bru" || parsed.Files[1].Path != "bruno.json" {
+		t.Fatalf("unexpected files %+v", parsed.Files)
+	}
+	fsys["generated/other.bru"] = &fstest.MapFile{Data: []byte("ignored too")}
+	if err := VerifyManifest(c, m, nil); err != nil {
+		t.Fatal(err.Error())
+	}
+	fsys["Users/Get User.bru"] = &fstest.MapFile{Data: []byte("get {\n  url: /users/2\n}\n")}
+	if err := VerifyManifest(c, m, nil); err == nil || err.Error() != "manifest: Users/Get User.bru was modified" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := Manifest(&Collection{}, nil); err == nil {
+		t.Fatal("should have failed without a filesystem")
+	}
+}
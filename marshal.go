@@ -0,0 +1,177 @@
+package bru
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Marshal encodes a struct into a bru file.
+// Each exported field tagged with `bru:"<tag>"` becomes a block, the tag being the bru tag of the block
+// (e.g. `bru:"meta"`, `bru:"body:json"`). The kind of the field must match the kind of the block:
+//   - maps, []DictionaryElement and structs are dictionary blocks
+//   - slices of strings and []ArrayElement are array blocks
+//   - strings are text blocks
+//
+// The fields of a struct used as a dictionary block are encoded as keys, in declaration order,
+// using their own bru tag as key or the field name if it has none.
+// Adding `,omitempty` to a tag skips the field when it is empty, fields tagged `bru:"-"` are always skipped.
+// Map keys are sorted to keep the output stable.
+func Marshal(v any, opts ...Option) ([]byte, error) {
+	blocks, err := marshalBlocks(v)
+	if err != nil {
+		return nil, err
+	}
+	return Write(blocks, opts...)
+}
+
+// marshalBlocks converts a tagged struct to the list of blocks it describes
+func marshalBlocks(v any) ([]ContentBlock, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, errors.New("bru: Marshal(nil)")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("bru: cannot marshal %s, expected a struct", rv.Type())
+	}
+	var blocks []ContentBlock
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, omitEmpty, ok := parseStructTag(field)
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		block, err := getBlockForTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("bru: field %s: %w", field.Name, err)
+		}
+		content, err := marshalContent(fv)
+		if err != nil {
+			return nil, fmt.Errorf("bru: field %s: %w", field.Name, err)
+		}
+		if err := block.SetContent(content); err != nil {
+			return nil, fmt.Errorf("bru: field %s: %w", field.Name, err)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// parseStructTag returns the bru tag of an exported field and whether it is omitempty.
+// ok is false if the field must be skipped.
+func parseStructTag(field reflect.StructField) (tag string, omitEmpty bool, ok bool) {
+	if !field.IsExported() {
+		return "", false, false
+	}
+	value, found := field.Tag.Lookup("bru")
+	if !found || value == "-" {
+		return "", false, false
+	}
+	tag, flags, _ := strings.Cut(value, ",")
+	return tag, flags == "omitempty", true
+}
+
+var (
+	dictionaryElementsType = reflect.TypeOf([]DictionaryElement(nil))
+	arrayElementsType      = reflect.TypeOf([]ArrayElement(nil))
+)
+
+// marshalContent converts a field value to the content of a block
+func marshalContent(v reflect.Value) (any, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, errors.New("nil value")
+		}
+		v = v.Elem()
+	}
+	switch v.Type() {
+	case dictionaryElementsType:
+		return v.Interface().([]DictionaryElement), nil
+	case arrayElementsType:
+		return v.Interface().([]ArrayElement), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice, reflect.Array:
+		values := make([]string, v.Len())
+		for i := range values {
+			s, err := marshalScalar(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values[i] = s
+		}
+		return values, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", v.Type().Key())
+		}
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		elements := make([]DictionaryElement, 0, len(keys))
+		for _, k := range keys {
+			s, err := marshalScalar(v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key())))
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, DictionaryElement{k, s, false})
+		}
+		return elements, nil
+	case reflect.Struct:
+		var elements []DictionaryElement
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("bru") == "-" {
+				continue
+			}
+			key, omitEmpty, ok := parseStructTag(field)
+			if !ok {
+				key = field.Name
+			}
+			fv := v.Field(i)
+			if omitEmpty && fv.IsZero() {
+				continue
+			}
+			s, err := marshalScalar(fv)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", key, err)
+			}
+			elements = append(elements, DictionaryElement{key, s, false})
+		}
+		return elements, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// marshalScalar converts a single value to its string representation in a bru file
+func marshalScalar(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Interface()), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
@@ -0,0 +1,65 @@
+package bru
+
+import "testing"
+
+func TestMarshal(t *testing.T) {
+	type meta struct {
+		Name string `bru:"name"`
+		Type string `bru:"type"`
+		Seq  int    `bru:"seq"`
+	}
+	request := struct {
+		Meta    meta              `bru:"meta"`
+		Get     map[string]string `bru:"get"`
+		Headers map[string]string `bru:"headers,omitempty"`
+		Body    string            `bru:"body:json"`
+		Secrets []string          `bru:"vars:secret"`
+		Ignored string
+	}{
+		Meta:    meta{"User Info", "http", 1},
+		Get:     map[string]string{"url": "{{baseUrl}}/users", "body": "json"},
+		Body:    `{"hello": "world"}`,
+		Secrets: []string{"token"},
+		Ignored: "toto",
+	}
+	encoded, err := Marshal(&request)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := `meta {
+  name: User Info
+  type: http
+  seq: 1
+}
+
+get {
+  body: json
+  url: {{baseUrl}}/users
+}
+
+body:json {
+{"hello": "world"}
+}
+
+vars:secret [
+  token
+]`
+	if string(encoded) != expected {
+		t.Fatalf("unexpected encoding:\n%s", string(encoded))
+	}
+}
+
+func TestMarshalWrongKind(t *testing.T) {
+	request := struct {
+		Meta string `bru:"meta"`
+	}{"toto"}
+	if _, err := Marshal(request); err == nil {
+		t.Fatal("should have failed")
+	}
+	request2 := struct {
+		Meta string `bru:"unknown"`
+	}{"toto"}
+	if _, err := Marshal(request2); err == nil {
+		t.Fatal("should have failed")
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package bru
+
+// mapFile reads the file at path in memory, as memory-mapping is not supported on this platform
+func mapFile(path string) ([]byte, func(), error) {
+	return readFile(path)
+}
@@ -0,0 +1,32 @@
+//go:build unix
+
+package bru
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile memory-maps the file at path read-only.
+// The returned function unmaps the file and must be called once the data is not used anymore.
+func mapFile(path string) ([]byte, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := info.Size()
+	if size <= 0 || int64(int(size)) != size || !info.Mode().IsRegular() {
+		// Nothing to map, or not mappable
+		return readFile(path)
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return readFile(path)
+	}
+	return data, func() { _ = syscall.Munmap(data) }, nil
+}
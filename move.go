@@ -0,0 +1,118 @@
+package bru
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Folder returns the folder at path in the collection
+func (c *Collection) Folder(path string) (*Folder, bool) {
+	var walk func(folders []*Folder) *Folder
+	walk = func(folders []*Folder) *Folder {
+		for _, f := range folders {
+			if f.Path == path {
+				return f
+			}
+			if found := walk(f.Folders); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	f := walk(c.Folders)
+	return f, f != nil
+}
+
+// MoveRequest moves the request whose file is at requestPath to the folder at folderPath,
+// the collection root if empty. The request keeps its file name, suffixed if the folder already has it,
+// and is numbered after the requests of the folder. The requests left behind are renumbered as by Renumber.
+// Nothing is written: Save creates the new file and removes the old one.
+func (c *Collection) MoveRequest(requestPath, folderPath string) (*Request, error) {
+	r, ok := c.Request(requestPath)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", requestPath, errRequestNotFound)
+	}
+	to := &c.Requests
+	if folderPath != "" {
+		f, ok := c.Folder(folderPath)
+		if !ok {
+			return nil, fmt.Errorf("%s: no such folder", folderPath)
+		}
+		to = &f.Requests
+	}
+	folders, _ := findRequest(c.Folders, c.Requests, r)
+	from := &c.Requests
+	if len(folders) > 0 {
+		from = &folders[len(folders)-1].Requests
+	}
+	if from == to {
+		return r, nil
+	}
+	for i, candidate := range *from {
+		if candidate == r {
+			*from = append((*from)[:i:i], (*from)[i+1:]...)
+			break
+		}
+	}
+	renumberRequests(*from)
+
+	taken := map[string]bool{}
+	last := 0
+	for _, sibling := range *to {
+		taken[strings.ToLower(strings.TrimSuffix(path.Base(sibling.Path), ".bru"))] = true
+		if seq, ok := sibling.Seq(); ok && seq > last {
+			last = seq
+		}
+	}
+	name := strings.TrimSuffix(path.Base(r.Path), ".bru")
+	r.Path = path.Join(folderPath, UniqueFilename(name, taken)+".bru")
+	r.setSeq(last + 1)
+	*to = append(*to, r)
+	renumberRequests(*to)
+	return r, nil
+}
+
+// RenameFolder renames the folder at folderPath: its directory is named after name with SafeFilename,
+// suffixed if another folder of its parent has it, and the name is set in the meta block of its folder.bru.
+// The paths of the folder and of its content are updated.
+// Nothing is written: Save creates the files of the new directory and removes the old ones.
+func (c *Collection) RenameFolder(folderPath, name string) (*Folder, error) {
+	f, ok := c.Folder(folderPath)
+	if !ok {
+		return nil, fmt.Errorf("%s: no such folder", folderPath)
+	}
+	parent := path.Dir(folderPath)
+	siblings := c.Folders
+	if p, ok := c.Folder(parent); ok {
+		siblings = p.Folders
+	} else {
+		parent = ""
+	}
+	taken := map[string]bool{}
+	for _, sibling := range siblings {
+		if sibling != f {
+			taken[strings.ToLower(sibling.Name)] = true
+		}
+	}
+	f.Name = UniqueFilename(name, taken)
+	setFolderPath(f, path.Join(parent, f.Name))
+	meta, ok := FindBlock(f.Blocks, "meta").(*DictionaryBlock)
+	if !ok {
+		meta = &DictionaryBlock{Name: "meta"}
+		f.Blocks = append([]ContentBlock{meta}, f.Blocks...)
+	}
+	setEntry(meta, "name", name, false)
+	return f, nil
+}
+
+// setFolderPath sets the path of the folder f and of its content
+func setFolderPath(f *Folder, p string) {
+	f.Path = p
+	for _, r := range f.Requests {
+		r.Path = path.Join(p, path.Base(r.Path))
+	}
+	for _, sub := range f.Folders {
+		setFolderPath(sub, path.Join(p, sub.Name))
+	}
+}
@@ -0,0 +1,88 @@
+package bru
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMoveRequest(t *testing.T) {
+	c, err := LoadCollection("testFiles")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	dir := t.TempDir()
+	if _, err := c.Save(dir); err != nil {
+		t.Fatal(err.Error())
+	}
+	r, err := c.MoveRequest("User/User Info.bru", "Repository")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if seq, _ := r.Seq(); r.Path != "Repository/User Info.bru" || seq != 4 {
+		t.Fatalf("unexpected moved request %s, seq %d", r.Path, seq)
+	}
+	if left := c.Folders[1].Requests; len(left) != 1 || left[0].Name != "User Repos" {
+		t.Fatalf("unexpected requests left %v", left)
+	} else if seq, _ := left[0].Seq(); seq != 1 {
+		t.Fatalf("requests left should be renumbered, got %d", seq)
+	}
+	if _, err := c.MoveRequest("Missing.bru", ""); err == nil {
+		t.Fatal("should have failed")
+	}
+	if _, err := c.MoveRequest("User/User Repos.bru", "Missing"); err == nil {
+		t.Fatal("should have failed")
+	}
+
+	changes, err := c.Save(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []FileChange{
+		{"Repository/User Info.bru", FileCreated},
+		{"User/User Info.bru", FileRemoved},
+		{"User/User Repos.bru", FileUpdated},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+}
+
+func TestRenameFolder(t *testing.T) {
+	c, err := LoadCollection("testFiles")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	dir := t.TempDir()
+	if _, err := c.Save(dir); err != nil {
+		t.Fatal(err.Error())
+	}
+	f, err := c.RenameFolder("Repository", "User")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if f.Name != "User_1" || f.Path != "User_1" || f.Requests[0].Path != "User_1/Search Repos.bru" {
+		t.Fatalf("unexpected renamed folder %+v", f)
+	}
+	if _, err := c.RenameFolder("Missing", "Other"); err == nil {
+		t.Fatal("should have failed")
+	}
+	if _, err := c.Save(dir); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Repository")); !os.IsNotExist(err) {
+		t.Fatalf("the old directory should be removed: %v", err)
+	}
+	reloaded, err := LoadCollection(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	renamed, ok := reloaded.Folder("User_1")
+	if !ok || len(renamed.Requests) != 3 {
+		t.Fatalf("unexpected saved folders %+v", reloaded.Folders)
+	}
+	if name, _ := FindBlock(renamed.Blocks, "meta").(*DictionaryBlock).Get("name"); name != "User" {
+		t.Fatalf("unexpected folder name %q", name)
+	}
+}
@@ -0,0 +1,82 @@
+package bru
+
+import (
+	"fmt"
+	"strings"
+)
+
+// multilineQuote delimits multiline dictionary values
+const multilineQuote = "'''"
+
+// unquoteMultiline returns the content of a triple quote delimited multiline value, dedented.
+// Other values are returned as is.
+func unquoteMultiline(raw string) string {
+	first, rest, found := strings.Cut(raw, "\n")
+	if !found || strings.TrimSpace(first) != multilineQuote {
+		return raw
+	}
+	end := strings.LastIndexByte(rest, '\n')
+	if end < 0 || strings.TrimSpace(rest[end+1:]) != multilineQuote {
+		// The scanner only accepts a closing quote at the start of a line
+		return raw
+	}
+	return dedent(rest[:end])
+}
+
+// multilineIndent returns the indentation removed from the lines of a multiline value by unquoteMultiline
+func multilineIndent(raw string) int {
+	_, rest, _ := strings.Cut(raw, "\n")
+	if indent := indentOf(strings.Split(rest[:strings.LastIndexByte(rest, '\n')], "\n")); indent > 0 {
+		return indent
+	}
+	return 0
+}
+
+// indentOf returns the indentation common to all the non blank lines, -1 if they are all blank
+func indentOf(lines []string) int {
+	indent := -1
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	return indent
+}
+
+// dedent removes the indentation common to all the non blank lines of s
+func dedent(s string) string {
+	lines := strings.Split(s, "\n")
+	indent := indentOf(lines)
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		} else if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// quoteMultiline formats a value containing line ends as a triple quote delimited multiline value,
+// for a dictionary entry indented by indent spaces.
+// Quotes cannot be escaped, so a value with a line holding only a triple quote is rejected.
+func quoteMultiline(value string, indent int) (string, error) {
+	var b strings.Builder
+	b.WriteString(multilineQuote + "\n")
+	for _, line := range strings.Split(value, "\n") {
+		if strings.TrimSpace(line) == multilineQuote {
+			return "", fmt.Errorf("multiline value cannot have a line of %s", multilineQuote)
+		}
+		if line != "" {
+			b.WriteString(strings.Repeat(" ", 2*indent))
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Repeat(" ", indent) + multilineQuote)
+	return b.String(), nil
+}
@@ -0,0 +1,152 @@
+package bru
+
+// Options holds the parameters shared by Read and Write.
+// It is not meant to be filled by hand, use the With* option constructors instead.
+type Options struct {
+	// Indent is the number of spaces used to indent block entries on encode
+	Indent int
+	// LineSep is appended after every dictionary entry except the last one on encode
+	LineSep string
+	// TrailingNewline adds a newline after the last block on encode
+	TrailingNewline bool
+	// CRLF writes CRLF line endings instead of LF on encode
+	CRLF bool
+	// SpillThreshold is the size in bytes above which text block content is moved to a temporary file on decode.
+	// 0 disables spilling
+	SpillThreshold int
+	// SpillDir is the directory of spilled content, the default temporary directory if empty
+	SpillDir string
+	// AllErrors keeps scanning after a syntax error on decode to report every error at once
+	AllErrors bool
+	// LenientTags reads blocks with unknown tags as GenericBlock instead of failing
+	LenientTags bool
+	// Comments receives the comments of the input on decode if not nil
+	Comments *[]Comment
+	// DryRun makes Collection.Save report its changes without writing anything
+	DryRun bool
+	// FailFast makes LoadCollection stop at the first file it cannot read
+	FailFast bool
+	// Sort orders the folders and requests read by LoadCollection
+	Sort SortPolicy
+}
+
+// An Option customizes the behaviour of Read and Write.
+type Option func(*Options)
+
+// defaultOptions returns the options used when none are given
+func defaultOptions() Options {
+	return Options{
+		Indent: 2,
+	}
+}
+
+// newOptions applies the given options over the default ones
+func newOptions(opts []Option) Options {
+	o := defaultOptions()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// WithOptions replaces all the options with the given ones.
+// Useful to forward an already built Options value.
+func WithOptions(options Options) Option {
+	return func(o *Options) {
+		*o = options
+	}
+}
+
+// WithIndent sets the number of spaces used to indent block entries.
+// A value of 0 or less is ignored.
+func WithIndent(indent int) Option {
+	return func(o *Options) {
+		if indent > 0 {
+			o.Indent = indent
+		}
+	}
+}
+
+// WithLineSeparator sets the string appended after every dictionary entry but the last one.
+// Dictionary values end with their line, so Read keeps the separator as part of the values.
+// Array values are always separated by commas.
+func WithLineSeparator(sep string) Option {
+	return func(o *Options) {
+		o.LineSep = sep
+	}
+}
+
+// WithTrailingNewline controls whether a newline is written after the last block.
+func WithTrailingNewline(enabled bool) Option {
+	return func(o *Options) {
+		o.TrailingNewline = enabled
+	}
+}
+
+// WithCRLF controls whether lines are ended with CRLF, as in files saved on Windows, instead of LF.
+// Read accepts both, use UsesCRLF on the input to keep its line endings.
+func WithCRLF(enabled bool) Option {
+	return func(o *Options) {
+		o.CRLF = enabled
+	}
+}
+
+// WithTextSpill moves the content of text blocks larger than threshold bytes to temporary files in dir
+// instead of keeping it in memory. An empty dir uses the default temporary directory.
+// Spilled blocks must be read through TextBlock.Reader and cleaned up with TextBlock.Release.
+func WithTextSpill(threshold int, dir string) Option {
+	return func(o *Options) {
+		o.SpillThreshold = threshold
+		o.SpillDir = dir
+	}
+}
+
+// WithAllErrors makes Read report every syntax error of the input instead of stopping at the first one.
+// After an error, scanning resumes at the next line starting with a tag.
+// The returned error joins all the SyntaxErrors, use errors.As or the Unwrap() []error method to access them.
+func WithAllErrors() Option {
+	return func(o *Options) {
+		o.AllErrors = true
+	}
+}
+
+// WithLenientTags makes Read accept tags it does not know, such as tags added by newer Bruno versions,
+// returning their blocks as *GenericBlock. The Tokenizer reads braced unknown blocks as text lines.
+func WithLenientTags() Option {
+	return func(o *Options) {
+		o.LenientTags = true
+	}
+}
+
+// WithComments makes Read store all the comments of the input in comments,
+// besides attaching them to their blocks.
+func WithComments(comments *[]Comment) Option {
+	return func(o *Options) {
+		o.Comments = comments
+	}
+}
+
+// WithDryRun makes Collection.Save only report the changes it would make.
+func WithDryRun() Option {
+	return func(o *Options) {
+		o.DryRun = true
+	}
+}
+
+// WithFailFast makes LoadCollection and LoadCollectionFS return the error of the first file
+// they cannot read, instead of loading the other files and reporting all the errors in a LoadReport.
+func WithFailFast() Option {
+	return func(o *Options) {
+		o.FailFast = true
+	}
+}
+
+// WithSortPolicy sets the order of the folders and requests read by LoadCollection and LoadCollectionFS,
+// SortBySeq by default.
+func WithSortPolicy(policy SortPolicy) Option {
+	return func(o *Options) {
+		o.Sort = policy
+	}
+}
@@ -126,3 +126,70 @@ tests {
 		t.Fatal(err.Error())
 	}
 }
+
+func TestSyntaxErrorPosition(t *testing.T) {
+	simpleFile := `meta {
+  url: https://toto.com
+  toto
+}
+`
+	err := checkValid([]byte(simpleFile), &scanner{})
+	syntaxErr, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("expected a syntax error, got %v", err)
+	}
+	if syntaxErr.Line != 3 || syntaxErr.Column != 7 || syntaxErr.Offset != 38 {
+		t.Fatalf("unexpected position: line %d, column %d, offset %d", syntaxErr.Line, syntaxErr.Column, syntaxErr.Offset)
+	}
+	t.Log(err.Error())
+}
+
+func TestAllErrors(t *testing.T) {
+	simpleFile := `meta {
+  toto
+}
+
+get {
+  url: https://toto.com
+}
+
+unknown {
+}
+
+vars:secret [
+  a
+  b
+]
+`
+	_, err := Read([]byte(simpleFile), WithAllErrors())
+	if err == nil {
+		t.Fatal("should have failed")
+	}
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %d: %v", len(errs), err)
+	}
+	lines := []int64{2, 9, 14}
+	for i, e := range errs {
+		if e.(*SyntaxError).Line != lines[i] {
+			t.Errorf("expected error %d at line %d, got %v", i, lines[i], e)
+		}
+	}
+}
+
+func TestTagCharacters(t *testing.T) {
+	simpleFile := `X_Vendor2-config:v1 {
+  retries: 3
+}
+`
+	if _, err := Read([]byte(simpleFile), WithLenientTags()); err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, invalid := range []string{"@meta {\n}", "meta {\n}\n}", "me$ta {\n}"} {
+		err := checkValid([]byte(invalid), &scanner{})
+		if err == nil {
+			t.Fatalf("%q should have failed", invalid)
+		}
+		t.Log(err.Error())
+	}
+}
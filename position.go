@@ -0,0 +1,58 @@
+package bru
+
+import "strings"
+
+// textPosition locates a decoded key, value or text content in the input it was read from
+type textPosition struct {
+	text   string // decoded text, the position being stale once the text changed
+	line   int64  // line of the first byte of text, starting at 1
+	column int64  // column of the first byte of text in bytes, starting at 1
+	indent int64  // indentation removed from the following lines, for multiline values
+}
+
+// locate returns the line and column in the input of the byte at offset i of the text
+func (p textPosition) locate(i int) (line, column int64) {
+	before := p.text[:i]
+	n := strings.Count(before, "\n")
+	if n == 0 {
+		return p.line, p.column + int64(i)
+	}
+	return p.line + int64(n), p.indent + int64(len(before)-strings.LastIndexByte(before, '\n'))
+}
+
+// blockPositions locate the content of a decoded block in its input
+type blockPositions struct {
+	keys   []textPosition // keys of the dictionary entries
+	values []textPosition // values of the entries, or content of a text block
+}
+
+// value returns the position of the value of entry i, if it is still s
+func (p blockPositions) value(i int, s string) (textPosition, bool) {
+	return positionOf(p.values, i, s)
+}
+
+// key returns the position of the key of entry i, if it is still s
+func (p blockPositions) key(i int, s string) (textPosition, bool) {
+	return positionOf(p.keys, i, s)
+}
+
+func positionOf(positions []textPosition, i int, s string) (textPosition, bool) {
+	if i >= len(positions) || positions[i].text != s {
+		return textPosition{}, false
+	}
+	return positions[i], true
+}
+
+// setBlockPositions sets the positions of a decoded block
+func setBlockPositions(block ContentBlock, p blockPositions) {
+	switch b := block.(type) {
+	case *DictionaryBlock:
+		b.positions = p
+	case *TextBlock:
+		b.positions = p
+	case *ArrayBlock:
+		b.positions = p
+	case *GenericBlock:
+		b.positions = p
+	}
+}
@@ -0,0 +1,123 @@
+package bru
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A BlockKind is the kind of content held by the blocks of a tag.
+type BlockKind int
+
+// The kinds of block in Bru
+const (
+	DictionaryKind BlockKind = iota // key: value lines between braces
+	TextKind                        // free text between braces
+	ArrayKind                       // comma separated values between brackets
+)
+
+func (k BlockKind) String() string {
+	switch k {
+	case DictionaryKind:
+		return "dictionary"
+	case TextKind:
+		return "text"
+	case ArrayKind:
+		return "array"
+	}
+	return fmt.Sprintf("BlockKind(%d)", int(k))
+}
+
+// registry maps every allowed tag name to the kind of its blocks
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]BlockKind{
+		"meta":                 DictionaryKind,
+		"get":                  DictionaryKind,
+		"post":                 DictionaryKind,
+		"put":                  DictionaryKind,
+		"delete":               DictionaryKind,
+		"patch":                DictionaryKind,
+		"options":              DictionaryKind,
+		"trace":                DictionaryKind,
+		"connect":              DictionaryKind,
+		"head":                 DictionaryKind,
+		"query":                DictionaryKind,
+		"headers":              DictionaryKind,
+		"auth":                 DictionaryKind,
+		"auth:basic":           DictionaryKind,
+		"auth:bearer":          DictionaryKind,
+		"auth:oauth2":          DictionaryKind,
+		"auth:awsv4":           DictionaryKind,
+		"auth:digest":          DictionaryKind,
+		"auth:ntlm":            DictionaryKind,
+		"auth:wsse":            DictionaryKind,
+		"auth:apikey":          DictionaryKind,
+		"body":                 TextKind,
+		"body:json":            TextKind,
+		"body:text":            TextKind,
+		"body:xml":             TextKind,
+		"body:sparql":          TextKind,
+		"body:graphql":         TextKind,
+		"body:graphql:vars":    TextKind,
+		"body:test":            TextKind,
+		"body:form-urlencoded": DictionaryKind,
+		"body:multipart-form":  DictionaryKind,
+		"body:file":            DictionaryKind,
+		"vars":                 DictionaryKind,
+		"vars:pre-request":     DictionaryKind,
+		"vars:post-response":   DictionaryKind,
+		"vars:secret":          ArrayKind,
+		"assert":               DictionaryKind,
+		"script:pre-request":   TextKind,
+		"script:post-response": TextKind,
+		"tests":                TextKind,
+		"docs":                 TextKind,
+		"settings":             DictionaryKind,
+	}
+)
+
+// RegisterTag teaches the package about a new tag, such as a vendor-specific or a newer Bruno block.
+// Registering a tag again with the same kind is a no-op.
+// RegisterTag panics if the name is not a valid tag name, or if the tag is already registered with another kind.
+func RegisterTag(name string, kind BlockKind) {
+	if !validTagName(name) {
+		panic("bru: invalid tag name " + name)
+	}
+	if kind < DictionaryKind || kind > ArrayKind {
+		panic("bru: invalid block kind " + kind.String())
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if existing, ok := registry[name]; ok && existing != kind {
+		panic(fmt.Sprintf("bru: tag %s already registered as %s", name, existing))
+	}
+	registry[name] = kind
+}
+
+// unregisterTag removes a tag from the registry, to undo RegisterTag in tests
+func unregisterTag(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(registry, name)
+}
+
+// LookupTag returns the kind of the blocks of a tag, and whether the tag is known
+func LookupTag(name string) (BlockKind, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	kind, ok := registry[name]
+	return kind, ok
+}
+
+// validTagName reports whether the scanner can read name as a tag
+func validTagName(name string) bool {
+	if name == "" || !isTagStart(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if !isTagChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
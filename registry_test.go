@@ -0,0 +1,41 @@
+package bru
+
+import "testing"
+
+func TestRegisterTag(t *testing.T) {
+	simpleFile := `x-vendor:config {
+  retries: 3
+}`
+	if _, err := Read([]byte(simpleFile)); err == nil {
+		t.Fatal("unknown tag should have failed")
+	}
+	RegisterTag("x-vendor:config", DictionaryKind)
+	t.Cleanup(func() { unregisterTag("x-vendor:config") })
+	RegisterTag("x-vendor:config", DictionaryKind)
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if read[0].GetName() != "x-vendor" || read[0].GetType() != "config" {
+		t.Fatalf("unexpected block %v", read[0])
+	}
+	decodeAndEncodeFileWithDefault([]byte(simpleFile), t)
+}
+
+func TestRegisterTagInvalid(t *testing.T) {
+	for _, register := range []func(){
+		func() { RegisterTag("-meta", DictionaryKind) },
+		func() { RegisterTag("my tag", DictionaryKind) },
+		func() { RegisterTag("meta", TextKind) },
+		func() { RegisterTag("toto", BlockKind(12)) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("RegisterTag should have panicked")
+				}
+			}()
+			register()
+		}()
+	}
+}
@@ -0,0 +1,88 @@
+package bru
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Seq returns the sequence number of the request in its folder, from its meta block
+func (r *Request) Seq() (int, bool) {
+	return seqOf(r.Blocks)
+}
+
+// seqOf reads the sequence number of the meta block of a request or folder file
+func seqOf(blocks []ContentBlock) (int, bool) {
+	meta, ok := FindBlock(blocks, "meta").(*DictionaryBlock)
+	if !ok {
+		return 0, false
+	}
+	value, ok := meta.Get("seq")
+	if !ok {
+		return 0, false
+	}
+	seq, err := strconv.Atoi(value)
+	return seq, err == nil
+}
+
+// setSeq sets the sequence number of the request, adding it to its meta block if needed.
+// It reports whether the number changed.
+func (r *Request) setSeq(seq int) bool {
+	value := strconv.Itoa(seq)
+	meta, ok := FindBlock(r.Blocks, "meta").(*DictionaryBlock)
+	if !ok {
+		meta = &DictionaryBlock{Name: "meta"}
+		r.Blocks = append([]ContentBlock{meta}, r.Blocks...)
+	}
+	for i, e := range meta.Content {
+		if !e.Disabled && e.Key == "seq" {
+			if e.Value == value {
+				return false
+			}
+			meta.Content[i].Value = value
+			return true
+		}
+	}
+	meta.Content = append(meta.Content, DictionaryElement{"seq", value, false})
+	return true
+}
+
+// Renumber rewrites the sequence numbers of the requests of the collection root and of all its folders,
+// numbering the requests of each folder from 1 without gaps nor duplicates.
+// Requests are sorted by their current number first, requests without one coming last,
+// and ties keeping their order. The paths of the requests whose number changed are returned.
+func (c *Collection) Renumber() []string {
+	root := &Folder{Folders: c.Folders, Requests: c.Requests}
+	changed := root.Renumber()
+	c.Requests = root.Requests
+	return changed
+}
+
+// Renumber rewrites the sequence numbers of the requests of the folder and of its sub folders,
+// as Collection.Renumber does.
+func (f *Folder) Renumber() []string {
+	changed := renumberRequests(f.Requests)
+	for _, sub := range f.Folders {
+		changed = append(changed, sub.Renumber()...)
+	}
+	return changed
+}
+
+// renumberRequests sorts the requests of a folder by sequence number and numbers them from 1,
+// returning the paths of the requests whose number changed
+func renumberRequests(requests []*Request) []string {
+	sort.SliceStable(requests, func(i, j int) bool {
+		si, oki := requests[i].Seq()
+		sj, okj := requests[j].Seq()
+		if oki != okj {
+			return oki
+		}
+		return si < sj
+	})
+	var changed []string
+	for i, r := range requests {
+		if r.setSeq(i + 1) {
+			changed = append(changed, r.Path)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,39 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRenumber(t *testing.T) {
+	c, err := LoadCollectionFS(fstest.MapFS{
+		"A.bru":         {Data: []byte("meta {\n  name: A\n  seq: 3\n}")},
+		"B.bru":         {Data: []byte("meta {\n  name: B\n  seq: 1\n}")},
+		"C.bru":         {Data: []byte("get {\n  url: https://toto.com\n}")},
+		"D.bru":         {Data: []byte("meta {\n  name: D\n  seq: 3\n}")},
+		"Folder/E.bru":  {Data: []byte("meta {\n  name: E\n  seq: 1\n}")},
+		"Folder/F.bru":  {Data: []byte("meta {\n  name: F\n  seq: 7\n}")},
+		"Folder/G.bru":  {Data: []byte("meta {\n  name: G\n  seq: 2\n}")},
+		"Other/H.bru":   {Data: []byte("meta {\n  name: H\n  seq: 1\n}")},
+		"Other/I/J.bru": {Data: []byte("meta {\n  name: J\n  seq: 4\n}")},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	changed := c.Renumber()
+	if !reflect.DeepEqual(changed, []string{"A.bru", "C.bru", "Folder/F.bru", "Other/I/J.bru"}) {
+		t.Fatalf("unexpected changed requests %v", changed)
+	}
+	var names []string
+	for _, r := range append(c.Requests, c.Folders[0].Requests...) {
+		seq, _ := r.Seq()
+		names = append(names, r.Name+":"+string(rune('0'+seq)))
+	}
+	if !reflect.DeepEqual(names, []string{"B:1", "A:2", "D:3", "C:4", "E:1", "G:2", "F:3"}) {
+		t.Fatalf("unexpected order %v", names)
+	}
+	if changed := c.Renumber(); len(changed) != 0 {
+		t.Fatalf("nothing should change, got %v", changed)
+	}
+}
@@ -0,0 +1,108 @@
+package bru
+
+import "strings"
+
+// Resolve returns r as it would be sent by Bruno, with the blocks it inherits from the collection
+// and the folders containing it merged into its own:
+//   - headers and vars:pre-request blocks are merged, the entries closest to the request taking precedence.
+//     Header names are compared case-insensitively.
+//   - an auth mode set to inherit is replaced by the mode of the closest folder or of the collection
+//     setting one, along with its auth block.
+//
+// Only enabled entries are kept. r itself is left unchanged, the returned request sharing its other blocks.
+func (c *Collection) Resolve(r *Request) (*Request, error) {
+	levels, err := c.levels(r)
+	if err != nil {
+		return nil, err
+	}
+	var headers, vars []DictionaryElement
+	for _, l := range levels {
+		headers = mergeElements(headers, dictionaryContent(FindBlock(l.blocks, "headers")), true)
+		vars = mergeElements(vars, PreRequestVars(l.blocks), false)
+	}
+
+	method, _ := FindBlock(r.Blocks, strings.ToLower(r.Method())).(*DictionaryBlock)
+	var authBlock ContentBlock
+	inherit := false
+	if method != nil {
+		if mode, _ := method.Get("auth"); mode == "inherit" {
+			inherit = true
+			mode, authBlock, _ = inheritedAuth(levels[:len(levels)-1])
+			method = cloneDictionary(method)
+			for i, e := range method.Content {
+				if !e.Disabled && e.Key == "auth" {
+					method.Content[i].Value = mode
+				}
+			}
+		}
+	}
+
+	resolved := &Request{Name: r.Name, Path: r.Path}
+	added := map[string]bool{}
+	for _, b := range r.Blocks {
+		switch tag := tagOf(b); {
+		case tag == "headers":
+			b = &DictionaryBlock{Name: "headers", Content: headers, Comments: blockComments(b)}
+		case tag == "vars:pre-request":
+			b = &DictionaryBlock{Name: "vars", Type: "pre-request", Content: vars, Comments: blockComments(b)}
+		case method != nil && tag == strings.ToLower(r.Method()):
+			b = method
+		case inherit && b.GetName() == "auth" && b.GetType() != "":
+			// Replaced by the inherited one
+			continue
+		}
+		added[tagOf(b)] = true
+		resolved.Blocks = append(resolved.Blocks, b)
+	}
+	if !added["headers"] && len(headers) > 0 {
+		resolved.Blocks = append(resolved.Blocks, &DictionaryBlock{Name: "headers", Content: headers})
+	}
+	if inherit && authBlock != nil {
+		resolved.Blocks = append(resolved.Blocks, authBlock)
+	}
+	if !added["vars:pre-request"] && len(vars) > 0 {
+		resolved.Blocks = append(resolved.Blocks, &DictionaryBlock{Name: "vars", Type: "pre-request", Content: vars})
+	}
+	return resolved, nil
+}
+
+// inheritedAuth returns the auth mode of the closest level setting one, the auth block of this mode and the level source.
+// The mode is none, set by the collection, if no level sets one.
+func inheritedAuth(levels []inheritLevel) (string, ContentBlock, Source) {
+	for i := len(levels) - 1; i >= 0; i-- {
+		auth, ok := FindBlock(levels[i].blocks, "auth").(*DictionaryBlock)
+		if !ok {
+			continue
+		}
+		if mode, _ := auth.Get("mode"); mode != "" && mode != "inherit" {
+			return mode, FindBlock(levels[i].blocks, "auth:"+mode), levels[i].source
+		}
+	}
+	return "none", nil, Source{SourceCollection, ""}
+}
+
+// mergeElements adds the enabled elements to merged, replacing the ones with the same key
+func mergeElements(merged []DictionaryElement, elements []DictionaryElement, foldCase bool) []DictionaryElement {
+	for _, e := range elements {
+		if e.Disabled {
+			continue
+		}
+		replaced := false
+		for i, m := range merged {
+			if m.Key == e.Key || foldCase && strings.EqualFold(m.Key, e.Key) {
+				merged[i], replaced = e, true
+			}
+		}
+		if !replaced {
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// cloneDictionary returns a copy of the block which can be modified without changing b
+func cloneDictionary(b *DictionaryBlock) *DictionaryBlock {
+	clone := *b
+	clone.Content = append([]DictionaryElement(nil), b.Content...)
+	return &clone
+}
@@ -0,0 +1,71 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestResolve(t *testing.T) {
+	c, err := LoadCollectionFS(fstest.MapFS{
+		"collection.bru": {Data: []byte("headers {\n  Accept: application/json\n  X-Client: bru\n}\n\n" +
+			"auth {\n  mode: bearer\n}\n\nauth:bearer {\n  token: {{token}}\n}\n\nvars:pre-request {\n  page: 1\n}")},
+		"Users/folder.bru": {Data: []byte("headers {\n  accept: text/plain\n  ~X-Debug: 1\n}\n\nauth {\n  mode: inherit\n}")},
+		"Users/Get User.bru": {Data: []byte("meta {\n  name: Get User\n}\n\nget {\n  url: {{baseUrl}}/users/1\n  auth: inherit\n}\n\n" +
+			"vars:pre-request {\n  page: 2\n}")},
+		"Users/Basic.bru": {Data: []byte("get {\n  url: {{baseUrl}}/basic\n  auth: basic\n}\n\n" +
+			"headers {\n  X-Client: other\n}\n\nauth:basic {\n  username: toto\n  password: secret\n}")},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	users := c.Folders[0]
+	original, _ := Write(users.Requests[1].Blocks)
+	resolved, err := c.Resolve(users.Requests[1])
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	written, err := Write(resolved.Blocks)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := `meta {
+  name: Get User
+}
+
+get {
+  url: {{baseUrl}}/users/1
+  auth: bearer
+}
+
+vars:pre-request {
+  page: 2
+}
+
+headers {
+  accept: text/plain
+  X-Client: bru
+}
+
+auth:bearer {
+  token: {{token}}
+}`
+	if string(written) != expected {
+		t.Fatalf("unexpected resolved request:\n%s", written)
+	}
+	if after, _ := Write(users.Requests[1].Blocks); !reflect.DeepEqual(original, after) {
+		t.Fatal("the request should not be modified")
+	}
+
+	resolved, err = c.Resolve(users.Requests[0])
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	headers := FindBlock(resolved.Blocks, "headers").(*DictionaryBlock).Content
+	if !reflect.DeepEqual(headers, []DictionaryElement{{"accept", "text/plain", false}, {"X-Client", "other", false}}) {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+	if _, err := FindBlock(resolved.Blocks, "auth:basic").(*DictionaryBlock).BasicAuth(); err != nil {
+		t.Fatalf("own auth should be kept: %s", err)
+	}
+}
@@ -0,0 +1,259 @@
+package bru
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// A ChangeKind is what Save does to a file
+type ChangeKind int
+
+// The changes made by Save
+const (
+	FileCreated ChangeKind = iota
+	FileUpdated
+	FileRemoved
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case FileCreated:
+		return "created"
+	case FileUpdated:
+		return "updated"
+	case FileRemoved:
+		return "removed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// A FileChange is a file written or removed by Save
+type FileChange struct {
+	Path string // path relative to the collection, with forward slashes
+	Kind ChangeKind
+}
+
+// Save writes the collection to the directory at path, creating it if needed.
+// Every request is written to its file in the directory of its folder, requests without a path
+// being named after them. The .bru files found in the directory which are not part of the collection
+// anymore are removed, along with the directories they leave empty. Files left unchanged are not rewritten.
+// The files which could not be loaded with the collection are neither removed nor overwritten.
+// The options are used to encode the files, with WithDryRun nothing is written.
+// Like in Bruno, files end with a newline unless WithTrailingNewline(false) is given.
+// Save returns the changes made, sorted by path.
+// Unless in dry run, it updates the path of the collection, folders and requests.
+func (c *Collection) Save(path string, opts ...Option) ([]FileChange, error) {
+	o := newOptions(append([]Option{WithTrailingNewline(true)}, opts...))
+	files := map[string][]byte{}
+	if c.Config != nil {
+		files[collectionConfigFile] = c.Config
+	}
+	if c.Blocks != nil {
+		data, err := Write(c.Blocks, WithOptions(o))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", collectionFile, err)
+		}
+		files[collectionFile] = data
+	}
+	for _, env := range c.Environments {
+		rel := environmentsDir + "/" + env.Name + ".bru"
+		data, err := EncodeEnvironment(env, WithOptions(o))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rel, err)
+		}
+		files[rel] = data
+	}
+	root := &Folder{Folders: c.Folders, Requests: c.Requests}
+	paths := map[any]string{}
+	if err := collectFiles(root, "", files, paths, o); err != nil {
+		return nil, err
+	}
+
+	ignore, err := c.ignore()
+	if err != nil {
+		return nil, err
+	}
+	existing, err := existingFiles(path, ignore)
+	if err != nil {
+		return nil, err
+	}
+	var changes []FileChange
+	for rel, data := range files {
+		if c.failedToLoad(rel) {
+			return nil, fmt.Errorf("%s: would overwrite a file which could not be loaded", rel)
+		}
+		old, err := os.ReadFile(filepath.Join(path, filepath.FromSlash(rel)))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			changes = append(changes, FileChange{rel, FileCreated})
+		case err != nil:
+			return nil, err
+		case !bytes.Equal(old, data):
+			changes = append(changes, FileChange{rel, FileUpdated})
+		}
+	}
+	for _, rel := range existing {
+		if _, ok := files[rel]; !ok && !c.failedToLoad(rel) {
+			changes = append(changes, FileChange{rel, FileRemoved})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	if o.DryRun {
+		return changes, nil
+	}
+
+	for _, change := range changes {
+		file := filepath.Join(path, filepath.FromSlash(change.Path))
+		if change.Kind == FileRemoved {
+			if err := os.Remove(file); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(file, files[change.Path], 0o644); err != nil {
+			return nil, err
+		}
+	}
+	if err := removeEmptyDirs(path, changes, paths); err != nil {
+		return nil, err
+	}
+	c.Path, c.fsys = path, os.DirFS(path)
+	for item, p := range paths {
+		switch i := item.(type) {
+		case *Folder:
+			i.Path = p
+		case *Request:
+			i.Path = p
+		}
+	}
+	return changes, nil
+}
+
+// collectFiles encodes the files of the folder f, whose directory is dir in the collection.
+// The paths of the folders and requests are stored in paths.
+func collectFiles(f *Folder, dir string, files map[string][]byte, paths map[any]string, o Options) error {
+	join := func(name string) string {
+		if dir == "" {
+			return name
+		}
+		return dir + "/" + name
+	}
+	if f.Blocks != nil && dir != "" {
+		data, err := Write(f.Blocks, WithOptions(o))
+		if err != nil {
+			return fmt.Errorf("%s: %w", join(folderFile), err)
+		}
+		files[join(folderFile)] = data
+	}
+	taken := map[string]bool{}
+	for _, r := range f.Requests {
+		if r.Path != "" {
+			taken[strings.ToLower(strings.TrimSuffix(path.Base(r.Path), ".bru"))] = true
+		}
+	}
+	for _, r := range f.Requests {
+		name := path.Base(r.Path)
+		if r.Path == "" {
+			name = UniqueFilename(r.Name, taken) + ".bru"
+		}
+		rel := join(name)
+		if _, ok := files[rel]; ok {
+			return fmt.Errorf("%s: two requests with the same file", rel)
+		}
+		data, err := Write(r.Blocks, WithOptions(o))
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		files[rel] = data
+		paths[r] = rel
+	}
+	for _, sub := range f.Folders {
+		rel := join(sub.Name)
+		paths[sub] = rel
+		if err := collectFiles(sub, rel, files, paths, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeEmptyDirs removes the directories left empty by the removed files, unless they are folders of the collection
+func removeEmptyDirs(root string, changes []FileChange, paths map[any]string) error {
+	folders := map[string]bool{}
+	for item, p := range paths {
+		if _, ok := item.(*Folder); ok {
+			folders[p] = true
+		}
+	}
+	var dirs []string
+	for _, change := range changes {
+		if change.Kind != FileRemoved {
+			continue
+		}
+		for dir := path.Dir(change.Path); dir != "." && !folders[dir]; dir = path.Dir(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	// Deepest first, so that parents are empty when reached
+	sort.Slice(dirs, func(i, j int) bool {
+		return len(dirs[i]) > len(dirs[j])
+	})
+	for _, dir := range dirs {
+		full := filepath.Join(root, filepath.FromSlash(dir))
+		if entries, err := os.ReadDir(full); err == nil && len(entries) == 0 {
+			if err := os.Remove(full); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// existingFiles lists the .bru files of the directory at root which LoadCollection would read,
+// relative to root. A missing directory has no files.
+func existingFiles(root string, ignore []string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == root {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if p != root && ignored(rel, ignore) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != root && (strings.HasPrefix(name, ".") || name == "node_modules") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(rel) == ".bru" && (!strings.HasPrefix(rel, environmentsDir+"/") || path.Dir(rel) == environmentsDir) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,135 @@
+package bru
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSaveCollection(t *testing.T) {
+	c, err := LoadCollection("testFiles")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	dir := filepath.Join(t.TempDir(), "collection")
+	changes, err := c.Save(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(changes) != 9 || changes[0] != (FileChange{"Repository/Repository Info.bru", FileCreated}) {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+	original, _ := os.ReadFile("testFiles/User/User Info.bru")
+	saved, _ := os.ReadFile(filepath.Join(dir, "User", "User Info.bru"))
+	if string(original) != string(saved) {
+		t.Fatalf("unexpected saved file:\n%s", saved)
+	}
+	if changes, err := c.Save(dir); err != nil || len(changes) != 0 {
+		t.Fatalf("nothing should change, got %v, %v", changes, err)
+	}
+
+	user := c.Folders[1]
+	user.Requests = append(user.Requests[1:], &Request{
+		Name:   "User Info",
+		Blocks: []ContentBlock{&DictionaryBlock{Name: "get", Content: []DictionaryElement{{"url", "{{baseUrl}}/user", false}}}},
+	})
+	expected := []FileChange{
+		{"User/User Info.bru", FileUpdated},
+	}
+	changes, err = c.Save(dir, WithDryRun())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("unexpected dry run changes %v", changes)
+	}
+	if saved, _ := os.ReadFile(filepath.Join(dir, "User", "User Info.bru")); string(saved) != string(original) {
+		t.Fatal("dry run should not write")
+	}
+
+	user.Requests = user.Requests[:1]
+	user.Requests[0].Name = "Repos"
+	c.Folders = c.Folders[1:]
+	changes, err = c.Save(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected = []FileChange{
+		{"Repository/Repository Info.bru", FileRemoved},
+		{"Repository/Repository Tags.bru", FileRemoved},
+		{"Repository/Search Repos.bru", FileRemoved},
+		{"User/User Info.bru", FileRemoved},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+	reloaded, err := LoadCollection(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(reloaded.Folders) != 1 || len(reloaded.Folders[0].Requests) != 1 {
+		t.Fatalf("unexpected saved collection %+v", reloaded)
+	}
+}
+
+func TestSavePartialCollection(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "good.bru"), []byte("get {\n  url: /\n}\n"), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.bru"), []byte("meta {\n  name"), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	c, err := LoadCollection(dir)
+	var report *LoadReport
+	if !errors.As(err, &report) {
+		t.Fatalf("expected a load report, got %v", err)
+	}
+	c.Requests[0].Blocks = append(c.Requests[0].Blocks, &DictionaryBlock{Name: "headers", Content: []DictionaryElement{{"Accept", "*/*", false}}})
+	changes, err := c.Save(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(changes, []FileChange{{"good.bru", FileUpdated}}) {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+	if broken, err := os.ReadFile(filepath.Join(dir, "broken.bru")); err != nil || string(broken) != "meta {\n  name" {
+		t.Fatalf("the file which could not be loaded should be kept: %q, %v", broken, err)
+	}
+	c.Requests = append(c.Requests, &Request{Name: "broken", Blocks: c.Requests[0].Blocks})
+	if _, err := c.Save(dir); err == nil {
+		t.Fatal("should not overwrite the file which could not be loaded")
+	}
+}
+
+func TestSaveUnchangedCollection(t *testing.T) {
+	dir := t.TempDir()
+	err := filepath.WalkDir("testFiles", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		file := filepath.Join(dir, strings.TrimPrefix(p, "testFiles"))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(file, data, 0o644)
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	c, err := LoadCollection(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if changes, err := c.Save(dir); err != nil || len(changes) != 0 {
+		t.Fatalf("nothing should change, got %v, %v", changes, err)
+	}
+}
@@ -1,6 +1,8 @@
 package bru
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"sync"
 )
@@ -27,8 +29,7 @@ func Valid(data []byte) bool {
 func checkValid(data []byte, scan *scanner) error {
 	scan.reset()
 	for _, c := range data {
-		scan.bytes++
-		if scan.step(scan, c) == scanError {
+		if scan.stepByte(c) == scanError {
 			return scan.err
 		}
 	}
@@ -38,14 +39,64 @@ func checkValid(data []byte, scan *scanner) error {
 	return nil
 }
 
+// checkValidAll verifies data like checkValid, but does not stop at the first error.
+// After an error, scanning resumes at the next line starting with a tag.
+// checkValidAll returns nil or all the SyntaxErrors joined.
+func checkValidAll(data []byte, scan *scanner) error {
+	return errors.Join(scanBlocks(data, scan, nil)...)
+}
+
+// scanBlocks scans all of data, resuming at the next line starting with a tag after each error.
+// If block is not nil, it is called with the bounds of each well-formed block.
+// scanBlocks returns the SyntaxErrors met.
+func scanBlocks(data []byte, scan *scanner, block func(start, end int)) []error {
+	scan.reset()
+	var errs []error
+	resync := false
+	start := 0
+	for i, c := range data {
+		if resync {
+			if i == 0 || data[i-1] != '\n' || !isTagStart(c) {
+				scan.skipByte(c)
+				continue
+			}
+			// Found the next top level tag, start over from there
+			scan.reset()
+			resync = false
+		}
+		switch scan.stepByte(c) {
+		case scanError:
+			errs = append(errs, scan.err)
+			resync = true
+		case scanBeginTag:
+			start = i
+		case scanEndBlock, scanEndArray:
+			if block != nil {
+				block(start, i+1)
+			}
+		}
+	}
+	if !resync && scan.eof() == scanError {
+		errs = append(errs, scan.err)
+	}
+	return errs
+}
+
 // A SyntaxError is a description of a Bru syntax error.
-// Unmarshal will return a SyntaxError if the Bru can't be parsed.
+// Read will return a SyntaxError if the Bru can't be parsed.
 type SyntaxError struct {
 	msg    string // description of error
 	Offset int64  // error occurred after reading Offset bytes
+	Line   int64  // line of the error, starting at 1
+	Column int64  // column of the error in bytes, starting at 1
 }
 
-func (e *SyntaxError) Error() string { return e.msg }
+func (e *SyntaxError) Error() string {
+	if e.Line == 0 {
+		return e.msg
+	}
+	return fmt.Sprintf("%s at line %d, column %d", e.msg, e.Line, e.Column)
+}
 
 // A scanner is a Bru scanning state machine.
 // Callers call scan.reset and then pass bytes in one at a time
@@ -75,6 +126,30 @@ type scanner struct {
 	// not set to zero by scan.reset)
 	bytes   int64
 	tagName []byte
+
+	// lines consumed before the current one, and value of bytes at the start of the current line,
+	// kept alongside bytes and not reset by scan.reset either
+	lines     int64
+	lineStart int64
+
+	// lenient accepts unknown tags, see WithLenientTags
+	lenient bool
+
+	// Comment being read: offset of its first byte (-1 if none), line,
+	// and state to resume with at its line end
+	commentStart  int64
+	commentLine   int64
+	commentReturn func(*scanner, byte) int
+
+	// captureComments records the comments read in comments
+	captureComments bool
+	comments        []scannedComment
+}
+
+// scannedComment is the position of a comment in the input, end excluded
+type scannedComment struct {
+	start, end int64
+	line       int64
 }
 
 var scannerPool = sync.Pool{
@@ -87,6 +162,8 @@ func newScanner() *scanner {
 	scan := scannerPool.Get().(*scanner)
 	// scan.reset by design doesn't set bytes to zero
 	scan.bytes = 0
+	scan.lines = 0
+	scan.lineStart = 0
 	scan.reset()
 	return scan
 }
@@ -121,6 +198,7 @@ const (
 	scanDictionaryKey          // started scanning dictionary key
 	scanDictionaryValue        // started scanning dictionary value
 	scanTextLine               // started scanning new text line
+	scanEntryComment           // the dictionary key or array value being scanned is a // comment
 
 	// Stop.
 	scanEnd   // top-level value ended *before* this byte; known to be first "stop" result
@@ -136,26 +214,7 @@ const (
 	parseDictionaryKey
 	parseDictionaryValue
 	parseTextValue
-)
-
-// tags is an array listing all allowed bru tag names
-var tags = []string{"meta", "vars:secret", "body", "tests", "get", "post", "put", "delete",
-	"options", "trace", "connect", "head", "query", "headers", "body:text", "body:xml",
-	"body:form-urlencoded", "body:multipart-form", "body:graphql", "body:graphql:vars", "script:pre-request",
-	"script:post-response", "body:test", "body:json", "assert", "vars"}
-
-// blockTypes is an array listing the types of the aforementioned tags
-// to access a tags type, juste use blockTypes[<index of tag>]
-var blockTypes = []int{dictionaryBlock, arrayBlock, textBlock, textBlock, dictionaryBlock, dictionaryBlock, dictionaryBlock, dictionaryBlock,
-	dictionaryBlock, dictionaryBlock, dictionaryBlock, dictionaryBlock, dictionaryBlock, dictionaryBlock, textBlock, textBlock,
-	dictionaryBlock, dictionaryBlock, textBlock, textBlock, textBlock,
-	textBlock, textBlock, textBlock, dictionaryBlock, dictionaryBlock}
-
-// The types of block in Bru
-const (
-	dictionaryBlock = iota
-	textBlock
-	arrayBlock
+	parseUnknownBlock // unknown tag in lenient mode, waiting for the delimiter to pick a block kind
 )
 
 // No nesting should take place, enforced to prevent stack overflow.
@@ -169,6 +228,39 @@ func (s *scanner) reset() {
 	s.err = nil
 	s.endBlock = false
 	s.tagName = nil
+	s.commentStart = -1
+}
+
+// stepByte feeds c to the scanner while keeping track of its position in the input.
+func (s *scanner) stepByte(c byte) int {
+	s.bytes++
+	op := s.step(s, c)
+	s.countLine(c)
+	return op
+}
+
+// skipByte moves past c without scanning it.
+func (s *scanner) skipByte(c byte) {
+	s.bytes++
+	s.countLine(c)
+}
+
+// countLine updates the line position after reading c
+func (s *scanner) countLine(c byte) {
+	if c == '\n' {
+		s.lines++
+		s.lineStart = s.bytes
+	}
+}
+
+// syntaxError builds a SyntaxError located at the last byte read
+func (s *scanner) syntaxError(msg string) *SyntaxError {
+	return &SyntaxError{
+		msg:    msg,
+		Offset: s.bytes,
+		Line:   s.lines + 1,
+		Column: s.bytes - s.lineStart,
+	}
 }
 
 // eof tells the scanner that the end of input has been reached.
@@ -177,6 +269,10 @@ func (s *scanner) eof() int {
 	if s.err != nil {
 		return scanError
 	}
+	if s.commentStart >= 0 {
+		// Comment on the last line
+		s.endComment(s.bytes)
+	}
 	if s.endBlock {
 		return scanEnd
 	}
@@ -185,7 +281,10 @@ func (s *scanner) eof() int {
 		return scanEnd
 	}
 	if s.err == nil {
-		s.err = &SyntaxError{"unexpected end of Bru input", s.bytes}
+		err := s.syntaxError("unexpected end of Bru input")
+		// The end of input is right after the last byte
+		err.Column++
+		s.err = err
 	}
 	return scanError
 }
@@ -209,6 +308,85 @@ func (s *scanner) popParseState() {
 	s.step = stateBeginBlockLine
 }
 
+// isTagStart reports whether c can start a tag name: a letter, a digit or an underscore
+func isTagStart(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_'
+}
+
+// isTagChar reports whether c can be part of a tag name, the ':' separating the name from the block type
+func isTagChar(c byte) bool {
+	return isTagStart(c) || c == '-' || c == ':'
+}
+
+// isCommentStart reports whether c starts a comment: # or //
+func isCommentStart(c byte) bool {
+	return c == '#' || c == '/'
+}
+
+// beginComment starts a comment with c, scanning resumes with next at the end of the line
+func (s *scanner) beginComment(c byte, next func(*scanner, byte) int) int {
+	s.commentStart = s.bytes - 1
+	s.commentLine = s.lines + 1
+	s.commentReturn = next
+	if c == '/' {
+		s.step = stateCommentSlash
+	} else {
+		s.step = stateInComment
+	}
+	return scanSkipSpace
+}
+
+// endComment records the comment being read, ending before offset end, and resumes scanning where it was
+func (s *scanner) endComment(end int64) {
+	if s.captureComments {
+		s.comments = append(s.comments, scannedComment{s.commentStart, end, s.commentLine})
+	}
+	s.commentStart = -1
+	s.step = s.commentReturn
+}
+
+// stateCommentSlash is the state after reading a `/` where a comment may start
+func stateCommentSlash(s *scanner, c byte) int {
+	if c == '/' {
+		s.step = stateInComment
+		return scanSkipSpace
+	}
+	return s.error(c, "after /, expecting // comment")
+}
+
+// beginEntrySlash starts a dictionary key or an array value with a `/`, which may turn out to start a // comment.
+// Scanning resumes with next at the end of the comment.
+func (s *scanner) beginEntrySlash(c byte, next func(*scanner, byte) int) int {
+	s.beginComment(c, next)
+	s.step = stateEntrySlash
+	return scanContinue
+}
+
+// stateEntrySlash is the state after a `/` starting a dictionary key or an array value:
+// the entry is a comment if another `/` follows, and a key or value starting with `/` otherwise
+func stateEntrySlash(s *scanner, c byte) int {
+	if c == '/' {
+		s.step = stateInComment
+		return scanEntryComment
+	}
+	s.commentStart = -1
+	if s.parseState[len(s.parseState)-1] == parseArrayValue {
+		s.step = stateInValue
+	} else {
+		s.step = stateInKey
+	}
+	return s.step(s, c)
+}
+
+// stateInComment is the state when reading a comment, which is skipped until the line end
+func stateInComment(s *scanner, c byte) int {
+	if c == '\n' {
+		s.endComment(s.bytes - 1)
+		return s.step(s, c)
+	}
+	return scanSkipSpace
+}
+
 func isSpace(c byte) bool {
 	return c <= ' ' && (c == ' ' || c == '\t' || c == '\r' || c == '\n')
 }
@@ -228,20 +406,22 @@ func stateBeginValueOrEmpty(s *scanner, c byte) int {
 func (s *scanner) checkTag(c byte) int {
 	tagName := string(s.tagName)
 	s.tagName = nil
-	for i, tag := range tags {
-		if tagName == tag {
-			s.step = stateWaitingForOpenBlock
-			// Tag found, determine what to parse next
-			switch blockTypes[i] {
-			case dictionaryBlock:
-				return s.pushParseState(c, parseDictionaryKey, scanEndTag)
-			case arrayBlock:
-				return s.pushParseState(c, parseArrayValue, scanEndTag)
-			case textBlock:
-				return s.pushParseState(c, parseTextValue, scanEndTag)
-			}
+	if kind, ok := LookupTag(tagName); ok {
+		s.step = stateWaitingForOpenBlock
+		// Tag found, determine what to parse next
+		switch kind {
+		case DictionaryKind:
+			return s.pushParseState(c, parseDictionaryKey, scanEndTag)
+		case ArrayKind:
+			return s.pushParseState(c, parseArrayValue, scanEndTag)
+		case TextKind:
+			return s.pushParseState(c, parseTextValue, scanEndTag)
 		}
 	}
+	if s.lenient {
+		s.step = stateWaitingForOpenBlock
+		return s.pushParseState(c, parseUnknownBlock, scanEndTag)
+	}
 	return s.error(c, "invalid tag name: "+tagName)
 }
 
@@ -251,6 +431,9 @@ func stateReadingTag(s *scanner, c byte) int {
 		// Checking that tag exists
 		return s.checkTag(c)
 	}
+	if !isTagChar(c) {
+		return s.error(c, "in tag name")
+	}
 	s.tagName = append(s.tagName, c)
 	return scanContinue
 }
@@ -278,6 +461,18 @@ func stateWaitingForOpenBlock(s *scanner, c byte) int {
 			s.step = stateOpenBlock
 			return scanBeginArray
 		}
+	case parseUnknownBlock:
+		// Braces are read as text, the content kind is guessed on decode
+		if c == '{' {
+			s.parseState[n-1] = parseTextValue
+			s.step = stateOpenBlock
+			return scanBeginText
+		}
+		if c == '[' {
+			s.parseState[n-1] = parseArrayValue
+			s.step = stateOpenBlock
+			return scanBeginArray
+		}
 	}
 	return s.error(c, "unexpected char after block name")
 }
@@ -287,7 +482,10 @@ func stateBeginBlockLine(s *scanner, c byte) int {
 	if isSpace(c) {
 		return scanSkipSpace
 	}
-	if c > 96 && c < 123 {
+	if isCommentStart(c) {
+		return s.beginComment(c, stateBeginBlockLine)
+	}
+	if isTagStart(c) {
 		// Start of a tagName
 		s.tagName = make([]byte, 0)
 		s.tagName = append(s.tagName, c)
@@ -295,8 +493,7 @@ func stateBeginBlockLine(s *scanner, c byte) int {
 		s.step = stateReadingTag
 		return scanBeginTag
 	}
-	// No block matched, end
-	return scanEnd
+	return s.error(c, "looking for beginning of tag")
 }
 
 // stateOpenBlock is the state after reading `{` or `[`.
@@ -350,7 +547,8 @@ func stateEndValue(s *scanner, c byte) int {
 		}
 		return s.error(c, "after dictionary key ")
 	case parseDictionaryValue:
-		if c == ',' || c == '\n' {
+		// Values end with their line only, commas are part of them
+		if c == '\n' {
 			s.parseState[n-1] = parseDictionaryKey
 			s.step = stateNewDictionaryPair
 			return scanDictionaryKey
@@ -373,6 +571,9 @@ func stateEndValue(s *scanner, c byte) int {
 			s.popParseState()
 			return scanEndArray
 		}
+		if isCommentStart(c) {
+			return s.beginComment(c, stateEndValue)
+		}
 		return s.error(c, "after array element")
 	}
 	return s.error(c, "")
@@ -388,6 +589,26 @@ func stateNewDictionaryPair(s *scanner, c byte) int {
 		s.popParseState()
 		return scanEndBlock
 	}
+	if c == '/' {
+		return s.beginEntrySlash(c, stateNewDictionaryPair)
+	}
+	if isCommentStart(c) {
+		return s.beginComment(c, stateNewDictionaryPair)
+	}
+	if c == '~' {
+		// Disabled entry
+		s.step = stateBeginKey
+		return scanContinue
+	}
+	return stateBeginKey(s, c)
+}
+
+// stateBeginKey is the state at the first character of a dictionary key
+func stateBeginKey(s *scanner, c byte) int {
+	if c == '"' {
+		s.step = stateInQuotedKey
+		return scanContinue
+	}
 	s.step = stateInKey
 	return stateInKey(s, c)
 }
@@ -400,15 +621,22 @@ func stateNewArrayValue(s *scanner, c byte) int {
 	// First char is an end block
 	if c == ']' {
 		s.popParseState()
-		return scanEndBlock
+		return scanEndArray
+	}
+	if c == '/' {
+		return s.beginEntrySlash(c, stateNewArrayValue)
+	}
+	if isCommentStart(c) {
+		return s.beginComment(c, stateNewArrayValue)
 	}
 	s.step = stateInValue
 	return stateInValue(s, c)
 }
 
-// stateNewDictionaryPair is the state when trying to read a new text block line
+// stateNewTextLine is the state when trying to read a new text block line
 func stateNewTextLine(s *scanner, c byte) int {
-	// If first char is end, end
+	// Like in Bruno, a closing brace in the first column ends the block,
+	// the braces of the content being indented or inside a line
 	if c == '}' {
 		s.popParseState()
 		return scanEndBlock
@@ -422,10 +650,42 @@ func stateInText(s *scanner, c byte) int {
 	if c == '\n' {
 		return stateEndValue(s, c)
 	}
-	//fmt.Printf("%c stateInText\n", c)
 	return scanContinue
 }
 
+// stateInQuotedKey is the state when reading a quoted key, which may contain colons
+func stateInQuotedKey(s *scanner, c byte) int {
+	if c == '"' {
+		s.step = stateEndQuotedKey
+		return scanContinue
+	}
+	if c == '\\' {
+		s.step = stateInQuotedKeyEsc
+		return scanContinue
+	}
+	if c < 0x20 {
+		return s.error(c, "in quoted key")
+	}
+	return scanContinue
+}
+
+// stateInQuotedKeyEsc is the state after reading `\` in a quoted key
+func stateInQuotedKeyEsc(s *scanner, c byte) int {
+	if c == '"' || c == '\\' {
+		s.step = stateInQuotedKey
+		return scanContinue
+	}
+	return s.error(c, "in quoted key escape code")
+}
+
+// stateEndQuotedKey is the state after the closing quote of a key
+func stateEndQuotedKey(s *scanner, c byte) int {
+	if c == ':' {
+		return stateEndValue(s, c)
+	}
+	return s.error(c, "after quoted key")
+}
+
 // stateInKey is the state when reading a key in a dictionary block line
 func stateInKey(s *scanner, c byte) int {
 	if c == ':' {
@@ -451,10 +711,104 @@ func stateBeginDictionaryValue(s *scanner, c byte) int {
 	if isSpace(c) {
 		return scanSkipSpace
 	}
+	if c == '\'' {
+		// Maybe the start of a ''' multiline value
+		s.step = stateInValueQuote
+		return scanContinue
+	}
+	s.step = stateInValue
+	return stateInValue(s, c)
+}
+
+// stateInValueQuote is the state after reading `'` at the start of a dictionary value.
+func stateInValueQuote(s *scanner, c byte) int {
+	if c == '\'' {
+		s.step = stateInValueQuoteQuote
+		return scanContinue
+	}
+	s.step = stateInValue
+	return stateInValue(s, c)
+}
+
+// stateInValueQuoteQuote is the state after reading two quotes at the start of a dictionary value.
+func stateInValueQuoteQuote(s *scanner, c byte) int {
+	if c == '\'' {
+		s.step = stateBeginMultiline
+		return scanContinue
+	}
+	s.step = stateInValue
+	return stateInValue(s, c)
+}
+
+// stateBeginMultiline is the state after reading three quotes at the start of a dictionary value.
+// Only spaces may follow until the end of the line, otherwise the value is a regular one.
+func stateBeginMultiline(s *scanner, c byte) int {
+	if c == '\n' {
+		s.step = stateMultilineLineStart
+		return scanContinue
+	}
+	if isSpace(c) {
+		return scanContinue
+	}
 	s.step = stateInValue
 	return stateInValue(s, c)
 }
 
+// stateMultilineLineStart is the state at the start of a line of a multiline value,
+// where the closing triple quote may be found after spaces.
+func stateMultilineLineStart(s *scanner, c byte) int {
+	if c == '\'' {
+		s.step = stateMultilineQuote
+		return scanContinue
+	}
+	if isSpace(c) {
+		return scanContinue
+	}
+	s.step = stateInMultiline
+	return scanContinue
+}
+
+// stateInMultiline is the state when reading a line of a multiline value
+func stateInMultiline(s *scanner, c byte) int {
+	if c == '\n' {
+		s.step = stateMultilineLineStart
+	}
+	return scanContinue
+}
+
+// stateMultilineQuote is the state after reading `'` at the start of a line of a multiline value.
+func stateMultilineQuote(s *scanner, c byte) int {
+	if c == '\'' {
+		s.step = stateMultilineQuoteQuote
+		return scanContinue
+	}
+	s.step = stateInMultiline
+	return stateInMultiline(s, c)
+}
+
+// stateMultilineQuoteQuote is the state after reading two quotes at the start of a line of a multiline value.
+func stateMultilineQuoteQuote(s *scanner, c byte) int {
+	if c == '\'' {
+		s.step = stateEndMultiline
+		return scanContinue
+	}
+	s.step = stateInMultiline
+	return stateInMultiline(s, c)
+}
+
+// stateEndMultiline is the state after reading the closing triple quote of a multiline value.
+// The value ends with the line, unless other characters follow on it.
+func stateEndMultiline(s *scanner, c byte) int {
+	if c == '\n' {
+		return stateEndValue(s, c)
+	}
+	if isSpace(c) {
+		return scanContinue
+	}
+	s.step = stateInMultiline
+	return scanContinue
+}
+
 // stateInValue is the state when reading a value from a dictionary or array block line
 func stateInValue(s *scanner, c byte) int {
 	if c == '\\' {
@@ -464,6 +818,10 @@ func stateInValue(s *scanner, c byte) int {
 	if c == '\n' {
 		return stateEndValue(s, c)
 	}
+	if c == ',' && s.parseState[len(s.parseState)-1] == parseArrayValue {
+		// Array values are separated by commas
+		return stateEndValue(s, c)
+	}
 	if c < 0x20 {
 		return s.error(c, "in value literal")
 	}
@@ -534,7 +892,7 @@ func stateError(s *scanner, c byte) int {
 // error records an error and switches to the error state.
 func (s *scanner) error(c byte, context string) int {
 	s.step = stateError
-	s.err = &SyntaxError{"invalid character " + quoteChar(c) + " " + context, s.bytes}
+	s.err = s.syntaxError("invalid character " + quoteChar(c) + " " + context)
 	return scanError
 }
 
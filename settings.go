@@ -0,0 +1,120 @@
+package bru
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Settings holds the request settings of a settings block.
+// Fields are nil when the setting is not present in the block.
+type Settings struct {
+	EncodeURL       *bool
+	FollowRedirects *bool
+	MaxRedirects    *int
+	Timeout         *int // in milliseconds
+	// Other holds the settings not known by this package and the disabled ones, kept to be written back
+	Other []DictionaryElement
+	// order holds the keys of the entries read, empty for the entries kept in Other
+	order []string
+}
+
+// Settings reads the settings of a settings block.
+// Values are parsed strictly, whatever the locale: an invalid boolean or number, such as 1,5, is an error naming the key.
+func (t *DictionaryBlock) Settings() (Settings, error) {
+	var s Settings
+	if err := checkBlockTag(t, "settings", ""); err != nil {
+		return s, err
+	}
+	for _, e := range t.Content {
+		if e.Disabled {
+			s.Other = append(s.Other, e)
+			s.order = append(s.order, "")
+			continue
+		}
+		var err error
+		switch e.Key {
+		case "encodeUrl":
+			s.EncodeURL, err = parseBoolSetting(e)
+		case "followRedirects":
+			s.FollowRedirects, err = parseBoolSetting(e)
+		case "maxRedirects":
+			s.MaxRedirects, err = parseIntSetting(e)
+		case "timeout":
+			s.Timeout, err = parseIntSetting(e)
+		default:
+			s.Other = append(s.Other, e)
+			s.order = append(s.order, "")
+			continue
+		}
+		if err != nil {
+			return s, err
+		}
+		s.order = append(s.order, e.Key)
+	}
+	return s, nil
+}
+
+// Block returns the settings block holding the settings.
+// The entries of a block read with DictionaryBlock.Settings keep their position,
+// the settings added since being written after them.
+func (s Settings) Block() *DictionaryBlock {
+	known := map[string]DictionaryElement{}
+	var keys []string
+	set := func(key, value string) {
+		known[key] = DictionaryElement{key, value, false}
+		keys = append(keys, key)
+	}
+	if s.EncodeURL != nil {
+		set("encodeUrl", strconv.FormatBool(*s.EncodeURL))
+	}
+	if s.FollowRedirects != nil {
+		set("followRedirects", strconv.FormatBool(*s.FollowRedirects))
+	}
+	if s.MaxRedirects != nil {
+		set("maxRedirects", strconv.Itoa(*s.MaxRedirects))
+	}
+	if s.Timeout != nil {
+		set("timeout", strconv.Itoa(*s.Timeout))
+	}
+
+	block := &DictionaryBlock{Name: "settings"}
+	other := s.Other
+	written := map[string]bool{}
+	for _, key := range s.order {
+		if key == "" {
+			if len(other) > 0 {
+				block.Content = append(block.Content, other[0])
+				other = other[1:]
+			}
+			continue
+		}
+		// A setting removed since is not written
+		if e, ok := known[key]; ok && !written[key] {
+			block.Content = append(block.Content, e)
+			written[key] = true
+		}
+	}
+	for _, key := range keys {
+		if !written[key] {
+			block.Content = append(block.Content, known[key])
+		}
+	}
+	block.Content = append(block.Content, other...)
+	return block
+}
+
+func parseBoolSetting(e DictionaryElement) (*bool, error) {
+	b, err := strconv.ParseBool(e.Value)
+	if err != nil {
+		return nil, fmt.Errorf("settings %s: invalid boolean %q", e.Key, e.Value)
+	}
+	return &b, nil
+}
+
+func parseIntSetting(e DictionaryElement) (*int, error) {
+	i, err := strconv.Atoi(e.Value)
+	if err != nil {
+		return nil, fmt.Errorf("settings %s: invalid integer %q", e.Key, e.Value)
+	}
+	return &i, nil
+}
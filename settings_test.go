@@ -0,0 +1,82 @@
+package bru
+
+import "testing"
+
+func TestSettings(t *testing.T) {
+	simpleFile := `settings {
+  encodeUrl: true
+  timeout: 5000
+  ~followRedirects: false
+  custom: toto
+}`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	settings, err := read[0].(*DictionaryBlock).Settings()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if settings.EncodeURL == nil || !*settings.EncodeURL || settings.Timeout == nil || *settings.Timeout != 5000 {
+		t.Fatalf("unexpected settings %+v", settings)
+	}
+	if settings.FollowRedirects != nil || settings.MaxRedirects != nil || len(settings.Other) != 2 {
+		t.Fatalf("unexpected settings %+v", settings)
+	}
+	encoded, err := Write([]ContentBlock{settings.Block()})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(encoded) != simpleFile {
+		t.Fatalf("unexpected encoding:\n%s", string(encoded))
+	}
+}
+
+func TestSettingsKeepOrder(t *testing.T) {
+	read, err := Read([]byte(`settings {
+  custom: toto
+  timeout: 5000
+  ~followRedirects: false
+  encodeUrl: true
+}`))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	settings, err := read[0].(*DictionaryBlock).Settings()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	timeout, maxRedirects := 1000, 3
+	settings.Timeout, settings.MaxRedirects, settings.EncodeURL = &timeout, &maxRedirects, nil
+	encoded, err := Write([]ContentBlock{settings.Block()})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := `settings {
+  custom: toto
+  timeout: 1000
+  ~followRedirects: false
+  maxRedirects: 3
+}`
+	if string(encoded) != expected {
+		t.Fatalf("unexpected encoding:\n%s", string(encoded))
+	}
+}
+
+func TestSettingsInvalid(t *testing.T) {
+	cases := map[string]string{
+		"timeout: 1,5":          `settings timeout: invalid integer "1,5"`,
+		"maxRedirects: 1.000":   `settings maxRedirects: invalid integer "1.000"`,
+		"encodeUrl: yes":        `settings encodeUrl: invalid boolean "yes"`,
+		"followRedirects: Vrai": `settings followRedirects: invalid boolean "Vrai"`,
+	}
+	for entry, expected := range cases {
+		read, err := Read([]byte("settings {\n  " + entry + "\n}"))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if _, err := read[0].(*DictionaryBlock).Settings(); err == nil || err.Error() != expected {
+			t.Errorf("%s: unexpected error %v", entry, err)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package bru
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// A SnippetGenerator writes example code sending a request, for documentation.
+// Variables such as {{baseUrl}} are written as is.
+type SnippetGenerator interface {
+	// Language is the name of the language or tool of the snippets, such as "curl"
+	Language() string
+	// Generate returns the snippet sending r
+	Generate(r *Request) (string, error)
+}
+
+// SnippetGenerators returns the built-in generators: Go, Python requests, JavaScript fetch and curl
+func SnippetGenerators() []SnippetGenerator {
+	return []SnippetGenerator{GoSnippet{}, PythonSnippet{}, FetchSnippet{}, CurlSnippet{}}
+}
+
+// snippetRequest is what a snippet needs to know about a request
+type snippetRequest struct {
+	method  string
+	url     string
+	headers []DictionaryElement
+	body    string
+	hasBody bool
+}
+
+// bodyContentTypes are the content types sent with the text body modes
+var bodyContentTypes = map[string]string{
+	"json":    "application/json",
+	"text":    "text/plain",
+	"xml":     "application/xml",
+	"sparql":  "application/sparql-query",
+	"graphql": "application/json",
+}
+
+// newSnippetRequest reads the method, URL, headers and body of r
+func newSnippetRequest(r *Request) (snippetRequest, error) {
+	s := snippetRequest{method: r.Method(), url: r.URL(), headers: r.Headers()}
+	if s.method == "" {
+		return s, fmt.Errorf("snippet: request %s has no method block", r.Path)
+	}
+	mode := r.BodyMode()
+	switch mode {
+	case "":
+		return s, nil
+	case "json", "text", "xml", "sparql":
+		if b, ok := FindBlock(r.Blocks, "body:"+mode).(*TextBlock); ok {
+			// Text blocks content is indented in the file
+			s.body = dedent(b.Content)
+		}
+	case "graphql":
+		query := ""
+		if b, ok := FindBlock(r.Blocks, "body:graphql").(*TextBlock); ok {
+			query = dedent(b.Content)
+		}
+		payload := map[string]any{"query": query}
+		if b, ok := FindBlock(r.Blocks, "body:graphql:vars").(*TextBlock); ok && json.Valid([]byte(b.Content)) {
+			payload["variables"] = json.RawMessage(b.Content)
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return s, err
+		}
+		s.body = string(body)
+	case "formUrlEncoded":
+		values := url.Values{}
+		for _, e := range dictionaryContent(FindBlock(r.Blocks, "body:form-urlencoded")) {
+			if !e.Disabled {
+				values.Add(e.Key, e.Value)
+			}
+		}
+		s.body = values.Encode()
+		s.setContentType("application/x-www-form-urlencoded")
+	default:
+		return s, fmt.Errorf("snippet: unsupported body mode %s", mode)
+	}
+	s.hasBody = true
+	if contentType, ok := bodyContentTypes[mode]; ok {
+		s.setContentType(contentType)
+	}
+	return s, nil
+}
+
+// setContentType adds a Content-Type header unless the request already has one
+func (s *snippetRequest) setContentType(contentType string) {
+	for _, h := range s.headers {
+		if strings.EqualFold(h.Key, "Content-Type") {
+			return
+		}
+	}
+	s.headers = append(s.headers, DictionaryElement{"Content-Type", contentType, false})
+}
+
+// quoteJS quotes s as a JSON string, which is also a valid JavaScript and Python literal
+func quoteJS(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// GoSnippet generates Go programs using net/http
+type GoSnippet struct{}
+
+func (GoSnippet) Language() string {
+	return "go"
+}
+
+func (GoSnippet) Generate(r *Request) (string, error) {
+	s, err := newSnippetRequest(r)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n")
+	if s.hasBody {
+		b.WriteString("\t\"strings\"\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+	body := "nil"
+	if s.hasBody {
+		b.WriteString("\tbody := strings.NewReader(" + quoteGo(s.body) + ")\n")
+		body = "body"
+	}
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(s.method), strconv.Quote(s.url), body)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	for _, h := range s.headers {
+		fmt.Fprintf(&b, "\treq.Header.Set(%s, %s)\n", strconv.Quote(h.Key), strconv.Quote(h.Value))
+	}
+	b.WriteString("\tres, err := http.DefaultClient.Do(req)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tdefer res.Body.Close()\n")
+	b.WriteString("\tdata, err := io.ReadAll(res.Body)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tfmt.Println(res.Status)\n\tfmt.Println(string(data))\n}\n")
+	return b.String(), nil
+}
+
+// quoteGo quotes s as a Go string literal, using a raw string if possible to keep bodies readable
+func quoteGo(s string) string {
+	if strings.ContainsAny(s, "`\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// PythonSnippet generates Python scripts using the requests library
+type PythonSnippet struct{}
+
+func (PythonSnippet) Language() string {
+	return "python"
+}
+
+func (PythonSnippet) Generate(r *Request) (string, error) {
+	s, err := newSnippetRequest(r)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("import requests\n\nresponse = requests.request(\n")
+	fmt.Fprintf(&b, "    %s,\n    %s,\n", quoteJS(s.method), quoteJS(s.url))
+	if len(s.headers) > 0 {
+		b.WriteString("    headers={\n")
+		for _, h := range s.headers {
+			fmt.Fprintf(&b, "        %s: %s,\n", quoteJS(h.Key), quoteJS(h.Value))
+		}
+		b.WriteString("    },\n")
+	}
+	if s.hasBody {
+		fmt.Fprintf(&b, "    data=%s,\n", quoteJS(s.body))
+	}
+	b.WriteString(")\nprint(response.status_code)\nprint(response.text)\n")
+	return b.String(), nil
+}
+
+// FetchSnippet generates JavaScript code using fetch
+type FetchSnippet struct{}
+
+func (FetchSnippet) Language() string {
+	return "javascript"
+}
+
+func (FetchSnippet) Generate(r *Request) (string, error) {
+	s, err := newSnippetRequest(r)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "const response = await fetch(%s, {\n  method: %s,\n", quoteJS(s.url), quoteJS(s.method))
+	if len(s.headers) > 0 {
+		b.WriteString("  headers: {\n")
+		for _, h := range s.headers {
+			fmt.Fprintf(&b, "    %s: %s,\n", quoteJS(h.Key), quoteJS(h.Value))
+		}
+		b.WriteString("  },\n")
+	}
+	if s.hasBody {
+		fmt.Fprintf(&b, "  body: %s,\n", quoteJS(s.body))
+	}
+	b.WriteString("});\nconsole.log(response.status);\nconsole.log(await response.text());\n")
+	return b.String(), nil
+}
+
+// CurlSnippet generates curl command lines for POSIX shells
+type CurlSnippet struct{}
+
+func (CurlSnippet) Language() string {
+	return "curl"
+}
+
+func (CurlSnippet) Generate(r *Request) (string, error) {
+	s, err := newSnippetRequest(r)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "curl -X %s %s", s.method, quoteShell(s.url))
+	for _, h := range s.headers {
+		b.WriteString(" \\\n  -H " + quoteShell(h.Key+": "+h.Value))
+	}
+	if s.hasBody {
+		b.WriteString(" \\\n  --data-raw " + quoteShell(s.body))
+	}
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+// quoteShell quotes s between single quotes for a POSIX shell
+func quoteShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,103 @@
+package bru
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+const snippetFile = `meta {
+  name: Create User
+  type: http
+  seq: 1
+}
+
+post {
+  url: {{baseUrl}}/users
+  body: json
+}
+
+headers {
+  Authorization: Bearer it's me
+  ~X-Debug: 1
+}
+
+body:json {
+  {
+    "name": "toto"
+  }
+}`
+
+func snippetRequestOf(t *testing.T, file string) *Request {
+	blocks, err := Read([]byte(file))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return newRequest("Create User.bru", blocks)
+}
+
+func TestCurlSnippet(t *testing.T) {
+	snippet, err := CurlSnippet{}.Generate(snippetRequestOf(t, snippetFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := `curl -X POST '{{baseUrl}}/users' \
+  -H 'Authorization: Bearer it'\''s me' \
+  -H 'Content-Type: application/json' \
+  --data-raw '{
+  "name": "toto"
+}'
+`
+	if snippet != expected {
+		t.Fatalf("unexpected snippet:\n%s", snippet)
+	}
+}
+
+func TestGoSnippet(t *testing.T) {
+	snippet, err := GoSnippet{}.Generate(snippetRequestOf(t, snippetFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "main.go", snippet, 0); err != nil {
+		t.Fatalf("invalid Go snippet: %s\n%s", err, snippet)
+	}
+	if !strings.Contains(snippet, `http.NewRequest("POST", "{{baseUrl}}/users", body)`) {
+		t.Fatalf("unexpected snippet:\n%s", snippet)
+	}
+}
+
+func TestSnippetGenerators(t *testing.T) {
+	r := snippetRequestOf(t, snippetFile)
+	for _, g := range SnippetGenerators() {
+		snippet, err := g.Generate(r)
+		if err != nil {
+			t.Fatalf("%s: %s", g.Language(), err)
+		}
+		if !strings.Contains(snippet, "{{baseUrl}}/users") || !strings.Contains(snippet, "Bearer it") || strings.Contains(snippet, "X-Debug") {
+			t.Fatalf("%s: unexpected snippet:\n%s", g.Language(), snippet)
+		}
+	}
+	if _, err := (PythonSnippet{}).Generate(&Request{Path: "empty.bru"}); err == nil {
+		t.Fatal("should have failed without method")
+	}
+}
+
+func TestSnippetFormBody(t *testing.T) {
+	r := snippetRequestOf(t, `post {
+  url: https://toto.com
+  body: formUrlEncoded
+}
+
+body:form-urlencoded {
+  a: 1 2
+  b: &
+}`)
+	snippet, err := FetchSnippet{}.Generate(r)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !strings.Contains(snippet, `body: "a=1+2&b=%26"`) || !strings.Contains(snippet, `"Content-Type": "application/x-www-form-urlencoded"`) {
+		t.Fatalf("unexpected snippet:\n%s", snippet)
+	}
+}
@@ -0,0 +1,57 @@
+package bru
+
+import "sort"
+
+// A SortPolicy orders the folders and requests of a collection, and so every API iterating over them
+// such as Find, Variables or ToK6. Ties are always broken by path, so the order never depends on the filesystem.
+type SortPolicy int
+
+const (
+	// SortBySeq orders by the seq entry of the meta block, items without one coming last, then by name and path.
+	// Folders read their seq from folder.bru, as in Bruno.
+	SortBySeq SortPolicy = iota
+	// SortByName orders by name, then by path
+	SortByName
+	// SortByPath orders by path
+	SortByPath
+)
+
+// Sort orders the folders and requests of the collection and of all its folders with the policy.
+// LoadCollection sorts with the policy of WithSortPolicy, SortBySeq by default.
+func (c *Collection) Sort(policy SortPolicy) {
+	root := &Folder{Folders: c.Folders, Requests: c.Requests}
+	root.Sort(policy)
+}
+
+// Sort orders the folders and requests of the folder and of its sub folders, as Collection.Sort does.
+func (f *Folder) Sort(policy SortPolicy) {
+	sort.Slice(f.Requests, func(i, j int) bool {
+		a, b := f.Requests[i], f.Requests[j]
+		return policy.less(a.Blocks, b.Blocks, a.Name, b.Name, a.Path, b.Path)
+	})
+	sort.Slice(f.Folders, func(i, j int) bool {
+		a, b := f.Folders[i], f.Folders[j]
+		return policy.less(a.Blocks, b.Blocks, a.Name, b.Name, a.Path, b.Path)
+	})
+	for _, sub := range f.Folders {
+		sub.Sort(policy)
+	}
+}
+
+// less reports whether the item with the blocks, name and path ending in 1 comes before the one ending in 2
+func (p SortPolicy) less(blocks1, blocks2 []ContentBlock, name1, name2, path1, path2 string) bool {
+	if p == SortBySeq {
+		seq1, ok1 := seqOf(blocks1)
+		seq2, ok2 := seqOf(blocks2)
+		if ok1 != ok2 {
+			return ok1
+		}
+		if seq1 != seq2 {
+			return seq1 < seq2
+		}
+	}
+	if p != SortByPath && name1 != name2 {
+		return name1 < name2
+	}
+	return path1 < path2
+}
@@ -0,0 +1,54 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSortPolicy(t *testing.T) {
+	fsys := fstest.MapFS{
+		"b.bru":             {Data: []byte("meta {\n  name: Alpha\n  seq: 2\n}")},
+		"c.bru":             {Data: []byte("meta {\n  name: Alpha\n  seq: 2\n}")},
+		"d.bru":             {Data: []byte("meta {\n  name: Beta\n  seq: 1\n}")},
+		"a.bru":             {Data: []byte("meta {\n  name: Gamma\n}")},
+		"First/folder.bru":  {Data: []byte("meta {\n  name: First\n  seq: 2\n}")},
+		"Second/folder.bru": {Data: []byte("meta {\n  name: Second\n  seq: 1\n}")},
+	}
+	paths := func(c *Collection) []string {
+		var found []string
+		for _, f := range c.Folders {
+			found = append(found, f.Path)
+		}
+		for _, r := range c.Requests {
+			found = append(found, r.Path)
+		}
+		return found
+	}
+	cases := []struct {
+		policy   SortPolicy
+		expected []string
+	}{
+		{SortBySeq, []string{"Second", "First", "d.bru", "b.bru", "c.bru", "a.bru"}},
+		{SortByName, []string{"First", "Second", "b.bru", "c.bru", "d.bru", "a.bru"}},
+		{SortByPath, []string{"First", "Second", "a.bru", "b.bru", "c.bru", "d.bru"}},
+	}
+	for _, tc := range cases {
+		c, err := LoadCollectionFS(fsys, WithSortPolicy(tc.policy))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if found := paths(c); !reflect.DeepEqual(found, tc.expected) {
+			t.Fatalf("unexpected order %v with policy %d", found, tc.policy)
+		}
+	}
+
+	c, err := LoadCollectionFS(fsys, WithSortPolicy(SortByPath))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	c.Sort(SortBySeq)
+	if found := paths(c); !reflect.DeepEqual(found, cases[0].expected) {
+		t.Fatalf("unexpected order %v after Sort", found)
+	}
+}
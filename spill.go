@@ -0,0 +1,93 @@
+package bru
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+// textWriter accumulates the lines of a text block.
+// Once the content grows above the spill threshold, it is moved to a temporary file
+// and every following line is written there instead of being kept in memory.
+type textWriter struct {
+	opts  Options
+	buf   bytes.Buffer
+	file  *os.File
+	lines int
+}
+
+// writeLine appends a line to the text content, spilling to disk if needed
+func (w *textWriter) writeLine(line []byte) error {
+	var out io.Writer = &w.buf
+	if w.file != nil {
+		out = w.file
+	}
+	if w.lines > 0 {
+		if _, err := out.Write([]byte{'\n'}); err != nil {
+			return err
+		}
+	}
+	w.lines++
+	if _, err := out.Write(line); err != nil {
+		return err
+	}
+	if w.file == nil && w.opts.SpillThreshold > 0 && w.buf.Len() > w.opts.SpillThreshold {
+		return w.spill()
+	}
+	return nil
+}
+
+// spill moves the buffered content to a temporary file
+func (w *textWriter) spill() error {
+	f, err := os.CreateTemp(w.opts.SpillDir, "bru-text-*")
+	if err != nil {
+		return err
+	}
+	w.file = f
+	if _, err = w.buf.WriteTo(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// finish returns the in memory content, or the path of the spill file if the content was spilled
+func (w *textWriter) finish() (content string, spillPath string, err error) {
+	if w.file == nil {
+		return w.buf.String(), "", nil
+	}
+	name := w.file.Name()
+	if err = w.file.Close(); err != nil {
+		os.Remove(name)
+		return "", "", err
+	}
+	return "", name, nil
+}
+
+// Spilled reports whether the content of the block was moved to a temporary file during decode.
+// When it is the case, Content is empty and the content must be accessed through Reader.
+func (t *TextBlock) Spilled() bool {
+	return t.spill != ""
+}
+
+// Reader returns a reader over the content of the block, whether it was spilled or not.
+// The caller must close the returned reader.
+func (t *TextBlock) Reader() (io.ReadCloser, error) {
+	if t.spill != "" {
+		return os.Open(t.spill)
+	}
+	return io.NopCloser(strings.NewReader(t.Content)), nil
+}
+
+// Release removes the temporary file holding the content of a spilled block.
+// It is a no-op for blocks kept in memory.
+func (t *TextBlock) Release() error {
+	if t.spill == "" {
+		return nil
+	}
+	err := os.Remove(t.spill)
+	t.spill = ""
+	return err
+}
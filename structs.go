@@ -2,25 +2,53 @@ package bru
 
 import "errors"
 
+// A DictionaryElement is a key: value line of a dictionary block.
+// Disabled is true for entries disabled with a '~' prefix, which is not part of Key.
 type DictionaryElement struct {
-	Key   string
-	Value string
+	Key      string
+	Value    string
+	Disabled bool
+}
+
+// An ArrayElement is a value of an array block.
+// Disabled is true for values disabled with a '~' prefix, which is not part of Value.
+type ArrayElement struct {
+	Value    string
+	Disabled bool
 }
 
 type DictionaryBlock struct {
 	Name    string
 	Type    string
 	Content []DictionaryElement
+	// Comments are the comments read before and inside the block, written back by the Encoder
+	Comments []Comment
+	// positions locate the entries in the input the block was read from
+	positions blockPositions
 }
+
+// A TextBlock holds raw content, such as a script or a body.
+// Like in Bruno, it ends at the first line starting with a closing brace,
+// so its content lines must not start with one: braces are not counted.
 type TextBlock struct {
 	Name    string
 	Type    string
 	Content string
+	// Comments are the comments read before the block, written back by the Encoder
+	Comments []Comment
+	// spill is the path of the temporary file holding the content, see Spilled
+	spill string
+	// positions locate the content in the input the block was read from
+	positions blockPositions
 }
 type ArrayBlock struct {
 	Name    string
 	Type    string
-	Content []string
+	Content []ArrayElement
+	// Comments are the comments read before and inside the block, written back by the Encoder
+	Comments []Comment
+	// positions locate the entries in the input the block was read from
+	positions blockPositions
 }
 
 func (t *DictionaryBlock) GetType() string {
@@ -47,6 +75,16 @@ func (t *ArrayBlock) GetName() string {
 	return t.Name
 }
 
+// Get returns the value of the first enabled element with the given key
+func (t *DictionaryBlock) Get(key string) (string, bool) {
+	for _, e := range t.Content {
+		if !e.Disabled && e.Key == key {
+			return e.Value, true
+		}
+	}
+	return "", false
+}
+
 func (t *DictionaryBlock) SetContent(content any) error {
 	switch c := content.(type) {
 	case []DictionaryElement:
@@ -60,16 +98,23 @@ func (t *TextBlock) SetContent(content any) error {
 	switch c := content.(type) {
 	case string:
 		t.Content = c
-		return nil
+		return t.Release()
 	}
 	return errors.New("wrong type to set for dictionary")
 }
 
 func (t *ArrayBlock) SetContent(content any) error {
 	switch c := content.(type) {
-	case []string:
+	case []ArrayElement:
 		t.Content = c
 		return nil
+	case []string:
+		// Plain values are all enabled
+		t.Content = make([]ArrayElement, len(c))
+		for i, v := range c {
+			t.Content[i] = ArrayElement{Value: v}
+		}
+		return nil
 	}
 	return errors.New("wrong type to set for dictionary")
 }
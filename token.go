@@ -0,0 +1,187 @@
+package bru
+
+import (
+	"io"
+)
+
+// A TokenKind is the kind of a Token.
+type TokenKind int
+
+const (
+	BeginBlock  TokenKind = iota // start of a block, Value is the tag name
+	Key                          // dictionary key
+	Value                        // dictionary or array value, may be empty for a dictionary
+	TextLine                     // line of a text block, without the line end
+	EndBlock                     // end of a block
+	CommentLine                  // comment, including its # or // marker
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case BeginBlock:
+		return "BeginBlock"
+	case Key:
+		return "Key"
+	case Value:
+		return "Value"
+	case TextLine:
+		return "TextLine"
+	case EndBlock:
+		return "EndBlock"
+	case CommentLine:
+		return "CommentLine"
+	}
+	return "TokenKind(?)"
+}
+
+// A Token is an element of a bru file, as returned by Tokenizer.Token.
+type Token struct {
+	Kind   TokenKind
+	Value  string
+	Offset int64 // offset of the first byte of the token in the input
+}
+
+// noBlock is the block kind of a tokenizer outside of blocks
+const noBlock BlockKind = -1
+
+// A Tokenizer reads a bru file token by token, without building the blocks.
+type Tokenizer struct {
+	data []byte
+	off  int
+	scan scanner
+	opts Options
+
+	block      BlockKind // kind of the current block, noBlock outside of blocks
+	tagStart   int       // start of the current tag
+	fieldStart int       // start of the current key or value, -1 if none
+	lineStart  int       // start of the current text line
+	pending    []Token
+	err        error
+}
+
+// NewTokenizer returns a tokenizer reading data.
+// As with Read, CRLF line endings and a leading UTF-8 byte order mark are accepted.
+func NewTokenizer(data []byte, opts ...Option) *Tokenizer {
+	t := &Tokenizer{
+		data:       normalizeInput(data),
+		opts:       newOptions(opts),
+		block:      noBlock,
+		fieldStart: -1,
+	}
+	t.scan.lenient = t.opts.LenientTags
+	t.scan.captureComments = true
+	t.scan.reset()
+	return t
+}
+
+// InputOffset returns the offset of the next byte to be read
+func (t *Tokenizer) InputOffset() int64 {
+	return int64(t.off)
+}
+
+// Token returns the next token of the input.
+// At the end of the input, Token returns io.EOF.
+// After a syntax error, every call returns the same *SyntaxError.
+func (t *Tokenizer) Token() (Token, error) {
+	for len(t.pending) == 0 {
+		if t.err != nil {
+			return Token{}, t.err
+		}
+		t.next()
+	}
+	tok := t.pending[0]
+	t.pending = t.pending[1:]
+	return tok, nil
+}
+
+// emit queues a token
+func (t *Tokenizer) emit(kind TokenKind, start, end int) {
+	t.pending = append(t.pending, Token{kind, string(t.data[start:end]), int64(start)})
+}
+
+// next scans until at least one token is available or an error is met
+func (t *Tokenizer) next() {
+	s := &t.scan
+	for len(t.pending) == 0 {
+		if t.off >= len(t.data) {
+			if s.eof() == scanError {
+				t.err = s.err
+			} else {
+				t.err = io.EOF
+			}
+			t.emitComments()
+			return
+		}
+		i, c := t.off, t.data[t.off]
+		t.off++
+		op := s.stepByte(c)
+		switch op {
+		case scanError:
+			t.err = s.err
+			return
+		case scanBeginTag:
+			t.tagStart = i
+		case scanEndTag:
+			t.emit(BeginBlock, t.tagStart, i)
+		case scanBeginDictionary:
+			t.block = DictionaryKind
+		case scanBeginArray:
+			t.block = ArrayKind
+		case scanBeginText:
+			t.block = TextKind
+			// The byte following the opening brace is the line end of the block line
+			t.lineStart = i + 2
+		case scanEndBlock, scanEndArray:
+			t.endField(i)
+			if t.block == TextKind && i > t.lineStart {
+				// Last line is not terminated by a line end
+				t.emit(TextLine, t.lineStart, i)
+			}
+			t.block = noBlock
+			t.pending = append(t.pending, Token{EndBlock, "", int64(i)})
+		case scanContinue:
+			if t.block != TextKind && t.block != noBlock && t.fieldStart < 0 {
+				t.fieldStart = i
+			}
+		case scanDictionaryValue:
+			// End of the key
+			key, disabled := cutDisabledPrefix(string(t.data[t.fieldStart:i]))
+			t.pending = append(t.pending, Token{Key, disabledPrefix(disabled) + unquoteKey(key), int64(t.fieldStart)})
+			t.fieldStart = -1
+		case scanDictionaryKey:
+			// End of the value, it may be empty
+			if t.fieldStart < 0 {
+				t.fieldStart = i
+			}
+			t.pending = append(t.pending, Token{Value, unquoteMultiline(string(t.data[t.fieldStart:i])), int64(t.fieldStart)})
+			t.fieldStart = -1
+		case scanEntryComment:
+			// The slash read as the start of a field starts a comment
+			t.fieldStart = -1
+		case scanArrayValue, scanSkipSpace:
+			t.endField(i)
+		}
+		if t.block == TextKind && c == '\n' && i >= t.lineStart {
+			t.emit(TextLine, t.lineStart, i)
+			t.lineStart = i + 1
+		}
+		t.emitComments()
+	}
+}
+
+// emitComments queues the comments read by the scanner
+func (t *Tokenizer) emitComments() {
+	for _, c := range commentsOf(t.data, t.scan.comments) {
+		t.pending = append(t.pending, Token{CommentLine, c.Text, c.Offset})
+	}
+	t.scan.comments = t.scan.comments[:0]
+}
+
+// endField emits the value being read, if any
+func (t *Tokenizer) endField(end int) {
+	if t.fieldStart < 0 {
+		return
+	}
+	t.emit(Value, t.fieldStart, end)
+	t.fieldStart = -1
+}
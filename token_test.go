@@ -0,0 +1,86 @@
+package bru
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestTokenizer(t *testing.T) {
+	simpleFile := `meta {
+  name: User Info
+  seq:
+}
+
+vars:secret [
+  access_key,
+  ~transactionId
+]
+
+tests {
+  test("status", function() {
+  });
+}`
+	expected := []Token{
+		{BeginBlock, "meta", 0},
+		{Key, "name", 9},
+		{Value, "User Info", 15},
+		{Key, "seq", 27},
+		{Value, "", 31},
+		{EndBlock, "", 32},
+		{BeginBlock, "vars:secret", 35},
+		{Value, "access_key", 51},
+		{Value, "~transactionId", 65},
+		{EndBlock, "", 80},
+		{BeginBlock, "tests", 83},
+		{TextLine, `  test("status", function() {`, 91},
+		{TextLine, "  });", 121},
+		{EndBlock, "", 127},
+	}
+	tokenizer := NewTokenizer([]byte(simpleFile))
+	for _, exp := range expected {
+		tok, err := tokenizer.Token()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if tok != exp {
+			t.Fatalf("expected %v, got %v", exp, tok)
+		}
+	}
+	if _, err := tokenizer.Token(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+}
+
+func TestTokenizerError(t *testing.T) {
+	tokenizer := NewTokenizer([]byte("unknown {\n}"))
+	_, err := tokenizer.Token()
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("expected a syntax error, got %v", err)
+	}
+}
+
+func TestTokenizerComments(t *testing.T) {
+	tokenizer := NewTokenizer([]byte("# head\nmeta {\n  # inner\n  seq: 1\n}\n// tail"))
+	var kinds []TokenKind
+	var comments []string
+	for {
+		tok, err := tokenizer.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		kinds = append(kinds, tok.Kind)
+		if tok.Kind == CommentLine {
+			comments = append(comments, tok.Value)
+		}
+	}
+	expected := []TokenKind{CommentLine, BeginBlock, CommentLine, Key, Value, EndBlock, CommentLine}
+	if !reflect.DeepEqual(kinds, expected) || !reflect.DeepEqual(comments, []string{"# head", "# inner", "// tail"}) {
+		t.Fatalf("unexpected tokens %v %v", kinds, comments)
+	}
+}
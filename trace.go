@@ -0,0 +1,75 @@
+package bru
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// opcodeNames are the names of the scanner opcodes, by value
+var opcodeNames = [...]string{
+	scanContinue:        "scanContinue",
+	scanSkipSpace:       "scanSkipSpace",
+	scanBeginTag:        "scanBeginTag",
+	scanEndTag:          "scanEndTag",
+	scanBeginArray:      "scanBeginArray",
+	scanBeginText:       "scanBeginText",
+	scanBeginDictionary: "scanBeginDictionary",
+	scanEndBlock:        "scanEndBlock",
+	scanEndArray:        "scanEndArray",
+	scanArrayValue:      "scanArrayValue",
+	scanDictionaryKey:   "scanDictionaryKey",
+	scanDictionaryValue: "scanDictionaryValue",
+	scanTextLine:        "scanTextLine",
+	scanEntryComment:    "scanEntryComment",
+	scanEnd:             "scanEnd",
+	scanError:           "scanError",
+}
+
+// TraceScan writes to w how the scanner reads data, one line per byte: its offset, line and column,
+// the byte, the state reading it and the opcode it returns. A last line shows the end of the input.
+// It is meant to debug the scanner, for instance when it disagrees with Bruno on a file.
+// The input is normalized as by Read, and WithLenientTags is taken into account.
+// TraceScan stops at the first syntax error and returns it, or the first write error.
+func TraceScan(data []byte, w io.Writer, opts ...Option) error {
+	data = normalizeInput(data)
+	scan := scanner{lenient: newOptions(opts).LenientTags}
+	scan.reset()
+	bw := bufio.NewWriter(w)
+	for _, c := range data {
+		line, col := scan.lines+1, scan.bytes-scan.lineStart+1
+		state := stateName(scan.step)
+		op := scan.stepByte(c)
+		fmt.Fprintf(bw, "%6d %4d:%-4d %-6s %-28s %s\n", scan.bytes-1, line, col, quoteChar(c), state, opcodeName(op))
+		if op == scanError {
+			bw.Flush()
+			return scan.err
+		}
+	}
+	state := stateName(scan.step)
+	op := scan.eof()
+	fmt.Fprintf(bw, "%6d %9s %-6s %-28s %s\n", scan.bytes, "", "EOF", state, opcodeName(op))
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if op == scanError {
+		return scan.err
+	}
+	return nil
+}
+
+// stateName returns the name of a scanner state function, such as stateInKey
+func stateName(step func(*scanner, byte) int) string {
+	name := runtime.FuncForPC(reflect.ValueOf(step).Pointer()).Name()
+	return name[strings.LastIndexByte(name, '.')+1:]
+}
+
+func opcodeName(op int) string {
+	if op >= 0 && op < len(opcodeNames) {
+		return opcodeNames[op]
+	}
+	return fmt.Sprintf("opcode(%d)", op)
+}
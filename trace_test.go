@@ -0,0 +1,32 @@
+package bru
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTraceScan(t *testing.T) {
+	var b strings.Builder
+	if err := TraceScan([]byte("meta {\n  seq: 1\n}"), &b); err != nil {
+		t.Fatal(err.Error())
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 18 {
+		t.Fatalf("expected a line per byte and one for the end, got:\n%s", b.String())
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "0 1:1 'm' stateBeginBlockLine scanBeginTag" {
+		t.Fatalf("unexpected first line %q", lines[0])
+	}
+	if fields := strings.Fields(lines[9]); strings.Join(fields, " ") != "9 2:3 's' stateOpenBlock scanContinue" {
+		t.Fatalf("unexpected line %q", lines[9])
+	}
+	if fields := strings.Fields(lines[17]); fields[1] != "EOF" || fields[3] != "scanEnd" {
+		t.Fatalf("unexpected last line %q", lines[17])
+	}
+
+	b.Reset()
+	err := TraceScan([]byte("meta {\n  seq\n}"), &b)
+	if err == nil || !strings.HasSuffix(strings.TrimSpace(b.String()), "scanError") {
+		t.Fatalf("unexpected error %v with trace:\n%s", err, b.String())
+	}
+}
@@ -0,0 +1,114 @@
+package bru
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// An IssueKind is the kind of problem found by Collection.Validate
+type IssueKind int
+
+// The problems found by Collection.Validate
+const (
+	IssueMissingMeta     IssueKind = iota // request without meta block
+	IssueDuplicateSeq                     // requests of a folder sharing a sequence number
+	IssueMissingMethod                    // request without method block
+	IssueMultipleMethods                  // request with more than one method block
+	IssueNameMismatch                     // file name not matching the name of the meta block
+	IssueLongPath                         // path too long to be opened by default on Windows
+)
+
+// maxWindowsPath is the length of the longest path Windows opens without long path support
+const maxWindowsPath = 259
+
+func (k IssueKind) String() string {
+	switch k {
+	case IssueMissingMeta:
+		return "missing meta"
+	case IssueDuplicateSeq:
+		return "duplicate seq"
+	case IssueMissingMethod:
+		return "missing method"
+	case IssueMultipleMethods:
+		return "multiple methods"
+	case IssueNameMismatch:
+		return "name mismatch"
+	case IssueLongPath:
+		return "long path"
+	}
+	return fmt.Sprintf("IssueKind(%d)", int(k))
+}
+
+// An Issue is a problem of a request file found by Collection.Validate
+type Issue struct {
+	Path    string // path of the request
+	Kind    IssueKind
+	Message string
+}
+
+func (i Issue) String() string {
+	return i.Path + ": " + i.Message
+}
+
+// Validate checks the requests of the collection for problems Bruno does not report clearly or fixes silently,
+// such as duplicate sequence numbers in a folder or file names not matching the request names.
+// Paths longer than Windows accepts by default are reported too, including the collection Path.
+// Issues are returned in the order of the requests.
+func (c *Collection) Validate() []Issue {
+	return validateFolder(&Folder{Folders: c.Folders, Requests: c.Requests}, c.Path)
+}
+
+func validateFolder(f *Folder, root string) []Issue {
+	var issues []Issue
+	seqs := map[int]string{}
+	for _, r := range f.Requests {
+		issues = append(issues, validateRequest(r)...)
+		if n := utf8.RuneCountInString(filepath.Join(root, filepath.FromSlash(r.Path))); n > maxWindowsPath {
+			issues = append(issues, Issue{r.Path, IssueLongPath, fmt.Sprintf("path is %d characters long, more than %d", n, maxWindowsPath)})
+		}
+		seq, ok := r.Seq()
+		if !ok {
+			continue
+		}
+		if other, taken := seqs[seq]; taken {
+			issues = append(issues, Issue{r.Path, IssueDuplicateSeq, fmt.Sprintf("seq %d is also used by %s", seq, other)})
+			continue
+		}
+		seqs[seq] = r.Path
+	}
+	for _, sub := range f.Folders {
+		issues = append(issues, validateFolder(sub, root)...)
+	}
+	return issues
+}
+
+func validateRequest(r *Request) []Issue {
+	var issues []Issue
+	meta, ok := FindBlock(r.Blocks, "meta").(*DictionaryBlock)
+	if !ok {
+		issues = append(issues, Issue{r.Path, IssueMissingMeta, "no meta block"})
+	} else if name, ok := meta.Get("name"); ok {
+		file := strings.TrimSuffix(path.Base(r.Path), ".bru")
+		if SafeFilename(name) != file {
+			issues = append(issues, Issue{r.Path, IssueNameMismatch, fmt.Sprintf("file name does not match name %q", name)})
+		}
+	}
+	var methods []string
+	for _, b := range r.Blocks {
+		for _, m := range httpMethods {
+			if tagOf(b) == m {
+				methods = append(methods, m)
+			}
+		}
+	}
+	switch {
+	case len(methods) == 0:
+		issues = append(issues, Issue{r.Path, IssueMissingMethod, "no method block"})
+	case len(methods) > 1:
+		issues = append(issues, Issue{r.Path, IssueMultipleMethods, "several method blocks: " + strings.Join(methods, ", ")})
+	}
+	return issues
+}
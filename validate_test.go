@@ -0,0 +1,51 @@
+package bru
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestValidate(t *testing.T) {
+	c, err := LoadCollection("testFiles")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if issues := c.Validate(); len(issues) != 0 {
+		t.Fatalf("unexpected issues %v", issues)
+	}
+	c, err = LoadCollectionFS(fstest.MapFS{
+		"A.bru":                                {Data: []byte("meta {\n  name: A\n  seq: 1\n}\n\nget {\n  url: /a\n}")},
+		"B.bru":                                {Data: []byte("meta {\n  name: Not B\n  seq: 1\n}\n\nget {\n  url: /b\n}\n\npost {\n  url: /b\n}")},
+		"Folder/C.bru":                         {Data: []byte("get {\n  url: /c\n}")},
+		"Folder/D.bru":                         {Data: []byte("meta {\n  name: D\n  seq: 1\n}")},
+		"Folder/E?.bru":                        {Data: []byte("meta {\n  name: E?\n}\n\nget {\n  url: /e\n}")},
+		"CON.bru":                              {Data: []byte("meta {\n  name: CON\n}\n\nget {\n  url: /con\n}")},
+		strings.Repeat("x", 251) + "/Long.bru": {Data: []byte("meta {\n  name: Long\n}\n\nget {\n  url: /long\n}")},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := []string{
+		`B.bru: file name does not match name "Not B"`,
+		"B.bru: several method blocks: get, post",
+		"B.bru: seq 1 is also used by A.bru",
+		`CON.bru: file name does not match name "CON"`,
+		"Folder/D.bru: no method block",
+		"Folder/C.bru: no meta block",
+		`Folder/E?.bru: file name does not match name "E?"`,
+		strings.Repeat("x", 251) + "/Long.bru: path is 260 characters long, more than 259",
+	}
+	issues := c.Validate()
+	if len(issues) != len(expected) {
+		t.Fatalf("unexpected issues %v", issues)
+	}
+	for i, issue := range issues {
+		if issue.String() != expected[i] {
+			t.Fatalf("unexpected issue %s, expected %s", issue, expected[i])
+		}
+	}
+	if issues[2].Kind != IssueDuplicateSeq || issues[2].Kind.String() != "duplicate seq" {
+		t.Fatalf("unexpected kind %v", issues[2].Kind)
+	}
+}
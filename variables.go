@@ -0,0 +1,113 @@
+package bru
+
+import (
+	"io"
+	"path"
+	"regexp"
+)
+
+// variableRef matches a {{name}} reference, spaces being allowed around the name
+var variableRef = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+
+// A VariableRef is a {{name}} reference found in the blocks of a file.
+// Line and Column locate its opening braces in the file, they are zero for content
+// that was not read from a file or that changed since.
+type VariableRef struct {
+	Name   string
+	Path   string // path of the file in the collection
+	Block  string // tag of the block, such as headers
+	Entry  int    // index of the dictionary or array entry, -1 in text blocks
+	Key    bool   // whether the reference is in the key of a dictionary entry instead of its value
+	Line   int64  // line of the reference, starting at 1
+	Column int64  // column of the reference in bytes, starting at 1
+}
+
+// Variables returns the references to variables in the request, in the order of its blocks.
+// Disabled entries are skipped.
+func (r *Request) Variables() []VariableRef {
+	return variableRefs(r.Path, r.Blocks)
+}
+
+// Variables returns the references to variables in the collection by variable name,
+// from collection.bru, the folder.bru files, the requests and the environments.
+func (c *Collection) Variables() map[string][]VariableRef {
+	vars := map[string][]VariableRef{}
+	add := func(refs []VariableRef) {
+		for _, ref := range refs {
+			vars[ref.Name] = append(vars[ref.Name], ref)
+		}
+	}
+	add(variableRefs(collectionFile, c.Blocks))
+	var walk func(folders []*Folder, requests []*Request)
+	walk = func(folders []*Folder, requests []*Request) {
+		for _, r := range requests {
+			add(r.Variables())
+		}
+		for _, f := range folders {
+			add(variableRefs(path.Join(f.Path, folderFile), f.Blocks))
+			walk(f.Folders, f.Requests)
+		}
+	}
+	walk(c.Folders, c.Requests)
+	for _, env := range c.Environments {
+		add(variableRefs(path.Join(environmentsDir, env.Name+".bru"), env.blocks()))
+	}
+	return vars
+}
+
+// variableRefs returns the references to variables in the blocks of the file at path
+func variableRefs(path string, blocks []ContentBlock) []VariableRef {
+	var refs []VariableRef
+	find := func(ref VariableRef, s string, pos textPosition, located bool) {
+		for _, m := range variableRef.FindAllStringSubmatchIndex(s, -1) {
+			ref.Name = s[m[2]:m[3]]
+			if located {
+				ref.Line, ref.Column = pos.locate(m[0])
+			}
+			refs = append(refs, ref)
+		}
+	}
+	for _, b := range blocks {
+		if g, ok := b.(*GenericBlock); ok {
+			b = g.Block()
+		}
+		ref := VariableRef{Path: path, Block: tagOf(b), Entry: -1}
+		switch c := b.(type) {
+		case *DictionaryBlock:
+			for i, e := range c.Content {
+				if e.Disabled {
+					continue
+				}
+				ref.Entry = i
+				ref.Key = true
+				pos, located := c.positions.key(i, e.Key)
+				find(ref, e.Key, pos, located)
+				ref.Key = false
+				pos, located = c.positions.value(i, e.Value)
+				find(ref, e.Value, pos, located)
+			}
+		case *ArrayBlock:
+			for i, e := range c.Content {
+				if !e.Disabled {
+					ref.Entry = i
+					pos, located := c.positions.value(i, e.Value)
+					find(ref, e.Value, pos, located)
+				}
+			}
+		case *TextBlock:
+			pos, located := c.positions.value(0, c.Content)
+			content := c.Content
+			if c.Spilled() {
+				// Read back from its temporary file, skipped if it cannot be
+				if r, err := c.Reader(); err == nil {
+					data, _ := io.ReadAll(r)
+					r.Close()
+					content = string(data)
+				}
+				pos.text = content
+			}
+			find(ref, content, pos, located)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,76 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRequestVariables(t *testing.T) {
+	blocks, err := Read([]byte(`get {
+  url: {{baseUrl}}/users/{{ id }}
+}
+
+headers {
+  {{headerName}}: {{token}}
+  ~X-Debug: {{debug}}
+}
+
+body:json {
+  {
+    "page": {{page}},
+    "empty": "{{}}"
+  }
+}
+
+body:form-urlencoded {
+  "{{field}}": '''
+    first
+      {{second}}
+  '''
+}`))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	r := &Request{Path: "Users/Get.bru", Blocks: blocks}
+	expected := []VariableRef{
+		{"baseUrl", "Users/Get.bru", "get", 0, false, 2, 8},
+		{"id", "Users/Get.bru", "get", 0, false, 2, 26},
+		{"headerName", "Users/Get.bru", "headers", 0, true, 6, 3},
+		{"token", "Users/Get.bru", "headers", 0, false, 6, 19},
+		{"page", "Users/Get.bru", "body:json", -1, false, 12, 13},
+		{"field", "Users/Get.bru", "body:form-urlencoded", 0, true, 18, 4},
+		{"second", "Users/Get.bru", "body:form-urlencoded", 0, false, 20, 7},
+	}
+	if refs := r.Variables(); !reflect.DeepEqual(refs, expected) {
+		t.Fatalf("unexpected references %v", refs)
+	}
+	// Positions are unknown once the content changed
+	r.Blocks[0].(*DictionaryBlock).Content[0].Value = "{{baseUrl}}/users"
+	if ref := r.Variables()[0]; ref.Name != "baseUrl" || ref.Line != 0 || ref.Column != 0 {
+		t.Fatalf("unexpected reference %v", ref)
+	}
+}
+
+func TestCollectionVariables(t *testing.T) {
+	c, err := LoadCollectionFS(fstest.MapFS{
+		"collection.bru":         {Data: []byte("headers {\n  Authorization: Bearer {{token}}\n}")},
+		"Users/folder.bru":       {Data: []byte("vars:pre-request {\n  path: {{baseUrl}}/users\n}")},
+		"Users/Get User.bru":     {Data: []byte("get {\n  url: {{path}}/1\n}")},
+		"Health.bru":             {Data: []byte("get {\n  url: {{baseUrl}}/health\n}")},
+		"environments/Local.bru": {Data: []byte("vars {\n  host: localhost\n  baseUrl: http://{{host}}\n}")},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	vars := c.Variables()
+	if len(vars) != 4 || len(vars["token"]) != 1 || vars["token"][0].Path != "collection.bru" || vars["path"][0].Path != "Users/Get User.bru" {
+		t.Fatalf("unexpected variables %v", vars)
+	}
+	if baseUrl := vars["baseUrl"]; len(baseUrl) != 2 || baseUrl[0].Path != "Health.bru" || baseUrl[1].Path != "Users/folder.bru" {
+		t.Fatalf("unexpected references %v", baseUrl)
+	}
+	if host := vars["host"]; len(host) != 1 || host[0].Path != "environments/Local.bru" || host[0].Line != 3 || host[0].Column != 19 {
+		t.Fatalf("unexpected references %v", host)
+	}
+}
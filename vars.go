@@ -0,0 +1,52 @@
+package bru
+
+// FindBlock returns the first block with the given tag (e.g. "vars:pre-request"), or nil if there is none
+func FindBlock(blocks []ContentBlock, tag string) ContentBlock {
+	for _, b := range blocks {
+		if tagOf(b) == tag {
+			return b
+		}
+	}
+	return nil
+}
+
+// PreRequestVars returns the variables of the vars:pre-request block of a request, set before it is sent
+func PreRequestVars(blocks []ContentBlock) []DictionaryElement {
+	return dictionaryContent(FindBlock(blocks, "vars:pre-request"))
+}
+
+// PostResponseVars returns the variables of the vars:post-response block of a request,
+// set from its response to chain requests
+func PostResponseVars(blocks []ContentBlock) []DictionaryElement {
+	return dictionaryContent(FindBlock(blocks, "vars:post-response"))
+}
+
+// SetPreRequestVars replaces the vars:pre-request block of a request, adding it if needed
+func SetPreRequestVars(blocks []ContentBlock, vars []DictionaryElement) []ContentBlock {
+	return setDictionaryBlock(blocks, "vars", "pre-request", vars)
+}
+
+// SetPostResponseVars replaces the vars:post-response block of a request, adding it if needed
+func SetPostResponseVars(blocks []ContentBlock, vars []DictionaryElement) []ContentBlock {
+	return setDictionaryBlock(blocks, "vars", "post-response", vars)
+}
+
+// dictionaryContent returns the content of a dictionary block, nil for other blocks
+func dictionaryContent(block ContentBlock) []DictionaryElement {
+	if d, ok := block.(*DictionaryBlock); ok {
+		return d.Content
+	}
+	return nil
+}
+
+// setDictionaryBlock sets the content of the dictionary block with the given name and type,
+// appending a new block if there is none
+func setDictionaryBlock(blocks []ContentBlock, name string, blockType string, content []DictionaryElement) []ContentBlock {
+	for _, b := range blocks {
+		if d, ok := b.(*DictionaryBlock); ok && d.Name == name && d.Type == blockType {
+			d.Content = content
+			return blocks
+		}
+	}
+	return append(blocks, &DictionaryBlock{Name: name, Type: blockType, Content: content})
+}
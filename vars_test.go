@@ -0,0 +1,40 @@
+package bru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRequestVars(t *testing.T) {
+	simpleFile := `get {
+  url: {{baseUrl}}/login
+}
+
+vars:pre-request {
+  userId: 1
+}
+
+vars:post-response {
+  token: res.body.token
+  ~refresh: res.body.refresh
+}`
+	read, err := Read([]byte(simpleFile))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if pre := PreRequestVars(read); !reflect.DeepEqual(pre, []DictionaryElement{{"userId", "1", false}}) {
+		t.Fatalf("unexpected pre-request vars %v", pre)
+	}
+	post := PostResponseVars(read)
+	if len(post) != 2 || post[1] != (DictionaryElement{"refresh", "res.body.refresh", true}) {
+		t.Fatalf("unexpected post-response vars %v", post)
+	}
+	read = SetPreRequestVars(read, []DictionaryElement{{"userId", "2", false}})
+	if len(read) != 3 || PreRequestVars(read)[0].Value != "2" {
+		t.Fatal("pre-request vars should have been replaced")
+	}
+	read = SetPostResponseVars(read[:1], post)
+	if len(read) != 2 || FindBlock(read, "vars:post-response") == nil {
+		t.Fatal("post-response vars should have been added")
+	}
+}